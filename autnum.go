@@ -1,26 +1,67 @@
 package openrdap
 
+import (
+	"reflect"
+)
+
 // Autnum represents information of Autonomous System registrations.
 //
 // Autnum is a topmost RDAP response object.
 // https://datatracker.ietf.org/doc/html/rfc7483#section-5.5
 type Autnum struct {
 	Common
-	Conformance     []string `json:"rdapConformance"`
-	ObjectClassName string   `json:"objectClassName"`
-	Notices         []Notice `json:"notices"`
+	Conformance     []string `json:"rdapConformance,omitempty"`
+	ObjectClassName string   `json:"objectClassName,omitempty"`
+	Notices         []Notice `json:"notices,omitempty"`
 
-	Handle      string   `json:"handle"`
+	Handle      string   `json:"handle,omitempty"`
 	StartAutnum uint32   `json:"startAutnum"`
 	EndAutnum   uint32   `json:"endAutnum"`
-	IPVersion   string   `json:"ipVersion"`
-	Name        string   `json:"name"`
-	Type        string   `json:"type"`
-	Status      []string `json:"status"`
-	Country     string   `json:"country"`
-	Entities    []Entity `json:"entities"`
-	Remarks     []Remark `json:"remarks"`
-	Links       []Link   `json:"links"`
-	Port43      string   `json:"port43"`
-	Events      []Event  `json:"events"`
+	IPVersion   string   `json:"ipVersion,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Status      []Status `json:"status,omitempty"`
+	Country     string   `json:"country,omitempty"`
+	Entities    []Entity `json:"entities,omitempty"`
+	Remarks     []Remark `json:"remarks,omitempty"`
+	Links       []Link   `json:"links,omitempty"`
+	Port43      string   `json:"port43,omitempty"`
+	Events      []Event  `json:"events,omitempty"`
+
+	// Extensions holds the members of extensions without a field above.
+	Extensions Extensions `json:"-"`
+}
+
+// HasStatus reports whether the autnum has the given status.
+func (a *Autnum) HasStatus(status Status) bool {
+	return HasStatus(a, status)
+}
+
+// GetEventByName returns the first event of the autnum with the given action.
+func (a *Autnum) GetEventByName(name string) *Event {
+	return EventByAction(a, name)
+}
+
+// GetEntityFromRole returns the first entity of the autnum with the given
+// role, searching nested entities at any depth.
+func (a *Autnum) GetEntityFromRole(role string) *Entity {
+	return EntityByRole(a, role)
+}
+
+// UnmarshalJSON for Autnum to keep extension members
+func (a *Autnum) UnmarshalJSON(data []byte) error {
+	type Alias Autnum
+	extensions, err := unmarshalObject(data, (*Alias)(a), reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+
+	a.Extensions = extensions
+	return nil
+}
+
+// MarshalJSON for Autnum to write back extension members
+func (a Autnum) MarshalJSON() ([]byte, error) {
+	type Alias Autnum
+	return marshalObject(Alias(a), a.Extensions)
 }
@@ -0,0 +1,107 @@
+package bootstrap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// WithCacheDir stores every fetched registry file in dir, together with the
+// ETag and Last-Modified headers it was served with.
+//
+// Subsequent fetches are revalidated with If-None-Match/If-Modified-Since, and
+// the cached copy is used when the registry server cannot be reached.
+func WithCacheDir(dir string) Option {
+	return func(c *Client) {
+		c.cache = &diskCache{dir: dir}
+	}
+}
+
+// cacheEntry is a registry file as retrieved from the registry server.
+type cacheEntry struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+	Expires      time.Time `json:"expires"`
+
+	body []byte
+}
+
+// diskCache stores each registry as two files: the raw registry JSON
+// (e.g. dns.json) and its HTTP metadata (e.g. dns.meta.json).
+type diskCache struct {
+	dir string
+}
+
+func (d *diskCache) bodyPath(regType RegistryType) string {
+	return filepath.Join(d.dir, regType.String()+".json")
+}
+
+func (d *diskCache) metaPath(regType RegistryType) string {
+	return filepath.Join(d.dir, regType.String()+".meta.json")
+}
+
+// load returns the cached entry for regType, or nil if nothing is cached.
+func (d *diskCache) load(regType RegistryType) (*cacheEntry, error) {
+	body, err := os.ReadFile(d.bodyPath(regType))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read cached registry %s: %w", regType.String(), err)
+	}
+
+	entry := &cacheEntry{body: body}
+
+	// the metadata is optional, without it the entry is simply not revalidated
+	meta, err := os.ReadFile(d.metaPath(regType))
+	if err == nil {
+		if err = json.Unmarshal(meta, entry); err != nil {
+			return nil, fmt.Errorf("unable to parse cache metadata for registry %s: %w", regType.String(), err)
+		}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("unable to read cache metadata for registry %s: %w", regType.String(), err)
+	}
+
+	return entry, nil
+}
+
+// store writes entry to the cache directory, replacing any previous entry.
+func (d *diskCache) store(regType RegistryType, entry *cacheEntry) error {
+	if err := os.MkdirAll(d.dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create cache directory: %w", err)
+	}
+
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err = writeFileAtomic(d.bodyPath(regType), entry.body); err != nil {
+		return err
+	}
+	return writeFileAtomic(d.metaPath(regType), meta)
+}
+
+// writeFileAtomic writes data to a temporary file and renames it into place,
+// so concurrent readers never observe a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,122 @@
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testDNSRegistry = `{
+  "version": "1.0",
+  "publication": "2024-10-01T00:00:00Z",
+  "description": "test registry",
+  "services": [
+    [["com", "net"], ["https://rdap.example.com/"]],
+    [["org"], ["https://rdap.example.org/"]]
+  ]
+}`
+
+func TestCacheRevalidation(t *testing.T) {
+	var requests, conditional int
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			conditional++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Tue, 01 Oct 2024 00:00:00 GMT")
+		w.Write([]byte(testDNSRegistry))
+	}))
+	defer mockServer.Close()
+
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+
+	first := NewBootstrapClient(mockServer.Client(), mockServer.URL+"/", WithCacheDir(cacheDir))
+	if _, err := first.FetchRegistryByType(ctx, DNS, true); err != nil {
+		t.Fatalf("initial fetch failed: %v", err)
+	}
+
+	// a new client sharing the cache directory must revalidate instead of downloading
+	second := NewBootstrapClient(mockServer.Client(), mockServer.URL+"/", WithCacheDir(cacheDir))
+	registry, err := second.FetchRegistryByType(ctx, DNS, true)
+	if err != nil {
+		t.Fatalf("revalidating fetch failed: %v", err)
+	}
+
+	if requests != 2 || conditional != 1 {
+		t.Errorf("expected 2 requests with 1 conditional, got %d and %d", requests, conditional)
+	}
+	if registry.Publication != "2024-10-01T00:00:00Z" {
+		t.Errorf("expected cached publication, got %q", registry.Publication)
+	}
+}
+
+func TestCacheFallback(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testDNSRegistry))
+	}))
+
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	baseURL := mockServer.URL + "/"
+
+	client := NewBootstrapClient(mockServer.Client(), baseURL, WithCacheDir(cacheDir))
+	if _, err := client.FetchRegistryByType(ctx, DNS, true); err != nil {
+		t.Fatalf("initial fetch failed: %v", err)
+	}
+
+	// the registry server becomes unreachable
+	mockServer.Close()
+
+	offline := NewBootstrapClient(mockServer.Client(), baseURL, WithCacheDir(cacheDir))
+	servers, err := offline.GetDomainRDAPServers(ctx, "example.org")
+	if err != nil {
+		t.Fatalf("expected cached registry to be used, got: %v", err)
+	}
+	if len(servers) != 1 || servers[0].String() != "https://rdap.example.org/" {
+		t.Errorf("unexpected servers: %v", servers)
+	}
+
+	uncached := NewBootstrapClient(mockServer.Client(), baseURL)
+	if _, err := uncached.GetDomainRDAPServers(ctx, "example.org"); err == nil {
+		t.Error("expected an error without a cache")
+	}
+}
+
+func TestCacheKeptOnInvalidResponse(t *testing.T) {
+	body := testDNSRegistry
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer mockServer.Close()
+
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	baseURL := mockServer.URL + "/"
+
+	client := NewBootstrapClient(mockServer.Client(), baseURL, WithCacheDir(cacheDir))
+	if _, err := client.FetchRegistryByType(ctx, DNS, true); err != nil {
+		t.Fatalf("initial fetch failed: %v", err)
+	}
+
+	// the server answers 200 with a truncated body
+	body = testDNSRegistry[:40]
+
+	fresh := NewBootstrapClient(mockServer.Client(), baseURL, WithCacheDir(cacheDir))
+	registry, err := fresh.FetchRegistryByType(ctx, DNS, true)
+	if err != nil {
+		t.Fatalf("expected cached registry to be used, got: %v", err)
+	}
+	if registry.Publication != "2024-10-01T00:00:00Z" {
+		t.Errorf("expected cached publication, got %q", registry.Publication)
+	}
+
+	entry, err := fresh.cache.load(DNS)
+	if err != nil || string(entry.body) != testDNSRegistry {
+		t.Errorf("expected the cached registry to be kept, got %v", err)
+	}
+}
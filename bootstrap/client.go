@@ -7,73 +7,213 @@ package bootstrap
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
+	"sync"
+	"time"
 )
 
 // Client implements an RDAP bootstrap client
 type Client struct {
 	httpClient              *http.Client
 	serviceRegistryIndexURL string
-	registries              map[RegistryType]*Registry
+	cache                   *diskCache
+	snapshotFallback        bool
+
+	// mu guards registries, entries, origins, overrides and supplementary,
+	// which may be swapped by a refresher while lookups are in progress.
+	mu            sync.RWMutex
+	registries    map[RegistryType]*Registry
+	entries       map[RegistryType]*cacheEntry
+	origins       map[RegistryType]registryOrigin
+	overrides     map[RegistryType]*Registry
+	supplementary *Registry
+}
+
+// registryOrigin records how a registry which was not fetched over HTTP got
+// loaded, so a refresher knows whether and when to replace it.
+type registryOrigin struct {
+	// pinned is set for registries installed with SetRegistry, which a
+	// refresher leaves in place.
+	pinned bool
+	// loadedAt is the time the registry was loaded, e.g. from the snapshot.
+	loadedAt time.Time
 }
 
-func NewBootstrapClient(httpClient *http.Client, serviceRegistryIndexURL string) *Client {
-	return &Client{
+// A Source identifies where the servers of a lookup came from.
+type Source int
+
+const (
+	// SourceIANA is the IANA registry, fetched, cached or from the snapshot.
+	SourceIANA Source = iota
+	// SourceOverride is an override added with AddOverride or SetOverrides.
+	SourceOverride
+	// SourceSupplementary is the supplementary DNS registry.
+	SourceSupplementary
+)
+
+func (s Source) String() string {
+	switch s {
+	case SourceIANA:
+		return "iana"
+	case SourceOverride:
+		return "override"
+	case SourceSupplementary:
+		return "supplementary"
+	default:
+		return "unknown"
+	}
+}
+
+// A Result holds the RDAP servers found by a lookup and their source.
+type Result struct {
+	URLs   []*url.URL
+	Source Source
+}
+
+// An Option configures optional behaviour of a Client.
+type Option func(*Client)
+
+func NewBootstrapClient(httpClient *http.Client, serviceRegistryIndexURL string, opts ...Option) *Client {
+	c := &Client{
 		httpClient:              httpClient,
 		serviceRegistryIndexURL: serviceRegistryIndexURL,
 		registries:              make(map[RegistryType]*Registry),
+		entries:                 make(map[RegistryType]*cacheEntry),
+		origins:                 make(map[RegistryType]registryOrigin),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 func (c *Client) FetchAllRegistries(ctx context.Context) error {
 	registryTypes := []RegistryType{DNS, IPv4, IPv6, ASN}
 	for _, regType := range registryTypes {
-		req, err := http.NewRequestWithContext(ctx, "GET", regType.ServiceRegistryIndexURL(c.serviceRegistryIndexURL), nil)
-		if err != nil {
-			return fmt.Errorf("unable to create request for registry %s: %w", regType.String(), err)
+		if _, err := c.FetchRegistryByType(ctx, regType, true); err != nil {
+			return err
 		}
+	}
 
-		resp, err := c.httpClient.Do(req)
-		if err != nil {
-			return fmt.Errorf("unable to retrieve registry %s: %w", regType.String(), err)
-		}
-		defer resp.Body.Close()
+	return nil
+}
 
-		if resp.StatusCode != 200 {
-			return fmt.Errorf("server returned non-200 status code: %s", resp.Status)
-		}
+func (c *Client) FetchRegistryByType(ctx context.Context, regType RegistryType, forceUpdate bool) (*Registry, error) {
+	if registry := c.registry(regType); registry != nil && !forceUpdate {
+		return registry, nil
+	}
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("unable to read response for registry %s: %w", regType.String(), err)
+	registry, entry, err := c.fetch(ctx, regType)
+	if err != nil {
+		entry = c.fallback(regType)
+		if entry == nil {
+			return nil, err
 		}
-
-		var registry Registry
-		if err = json.Unmarshal(body, &registry); err != nil {
-			return err
+		if registry, err = parseRegistry(regType, entry.body); err != nil {
+			return nil, err
 		}
+	}
 
-		c.registries[regType] = &registry
+	c.mu.Lock()
+	c.registries[regType] = registry
+	c.entries[regType] = entry
+	delete(c.origins, regType)
+	c.mu.Unlock()
 
+	return registry, nil
+}
+
+// registry returns the loaded registry for regType, or nil if it has not been
+// loaded yet.
+func (c *Client) registry(regType RegistryType) *Registry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.registries[regType]
+}
+
+// loadRegistry returns the loaded registry for regType, fetching it first if
+// necessary.
+func (c *Client) loadRegistry(ctx context.Context, regType RegistryType) (*Registry, error) {
+	if registry := c.registry(regType); registry != nil {
+		return registry, nil
 	}
+	return c.FetchRegistryByType(ctx, regType, true)
+}
 
-	return nil
+// fetch retrieves and parses the registry for regType without storing it in
+// the Client. The file is written to the cache directory only once it parsed,
+// so a truncated or garbled response cannot replace a good cached copy.
+func (c *Client) fetch(ctx context.Context, regType RegistryType) (*Registry, *cacheEntry, error) {
+	entry, err := c.download(ctx, regType, c.cached(regType))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	registry, err := parseRegistry(regType, entry.body)
+	if err != nil {
+		return nil, nil, err
+	}
+	c.storeCache(regType, entry)
+	return registry, entry, nil
 }
 
-func (c *Client) FetchRegistryByType(ctx context.Context, regType RegistryType, forceUpdate bool) (*Registry, error) {
-	if c.registries[regType] != nil && !forceUpdate {
-		return c.registries[regType], nil
+// cached returns the most recent copy of the registry file for regType, from
+// memory or from the cache directory, or nil if there is none.
+func (c *Client) cached(regType RegistryType) *cacheEntry {
+	c.mu.RLock()
+	cached := c.entries[regType]
+	c.mu.RUnlock()
+
+	if cached == nil && c.cache != nil {
+		// an unreadable cache entry is treated the same as a missing one
+		cached, _ = c.cache.load(regType)
+	}
+	return cached
+}
+
+// fallback returns the registry file to use when regType cannot be fetched:
+// the cached copy if there is one, otherwise the embedded snapshot if enabled.
+func (c *Client) fallback(regType RegistryType) *cacheEntry {
+	if cached := c.cached(regType); cached != nil {
+		return cached
+	}
+	if c.snapshotFallback {
+		if body, err := snapshotFile(regType); err == nil {
+			return &cacheEntry{body: body}
+		}
 	}
+	return nil
+}
+
+func parseRegistry(regType RegistryType, data []byte) (*Registry, error) {
 	var registry Registry
+	if err := json.Unmarshal(data, &registry); err != nil {
+		return nil, fmt.Errorf("unable to parse registry %s: %w", regType.String(), err)
+	}
+	return &registry, nil
+}
 
+// download retrieves the raw registry file. If cached is non-nil the request
+// is made conditional on the cached validators, and cached is returned when
+// the server reports it as unmodified.
+func (c *Client) download(ctx context.Context, regType RegistryType, cached *cacheEntry) (*cacheEntry, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", regType.ServiceRegistryIndexURL(c.serviceRegistryIndexURL), nil)
 	if err != nil {
 		return nil, fmt.Errorf("unable to create request for registry %s: %w", regType.String(), err)
 	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -81,6 +221,15 @@ func (c *Client) FetchRegistryByType(ctx context.Context, regType RegistryType,
 	}
 	defer resp.Body.Close()
 
+	now := time.Now()
+
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		revalidated := *cached
+		revalidated.FetchedAt = now
+		revalidated.Expires = expiresFromHeaders(resp.Header, now)
+		return &revalidated, nil
+	}
+
 	if resp.StatusCode != 200 {
 		return nil, fmt.Errorf("server returned non-200 status code: %s", resp.Status)
 	}
@@ -90,60 +239,176 @@ func (c *Client) FetchRegistryByType(ctx context.Context, regType RegistryType,
 		return nil, fmt.Errorf("unable to read response for registry %s: %w", regType.String(), err)
 	}
 
-	if err = json.Unmarshal(body, &registry); err != nil {
-		return nil, err
+	entry := &cacheEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    now,
+		Expires:      expiresFromHeaders(resp.Header, now),
+		body:         body,
 	}
+	return entry, nil
+}
 
-	c.registries[regType] = &registry
-
-	return c.registries[regType], nil
+// storeCache writes entry to the cache directory, if one is configured. A
+// cache that cannot be written must not prevent the registry from being used,
+// so errors are ignored.
+func (c *Client) storeCache(regType RegistryType, entry *cacheEntry) {
+	if c.cache == nil {
+		return
+	}
+	_ = c.cache.store(regType, entry)
 }
 
 func (c *Client) GetDomainRDAPServers(ctx context.Context, domain string) ([]*url.URL, error) {
-	var err error
-	if c.registries[DNS] == nil {
-		c.registries[DNS], err = c.FetchRegistryByType(ctx, DNS, true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch DNS service registry: %w", err)
-		}
-	}
-	return c.registries[DNS].getDNSServers(domain)
+	return urlsOf(c.LookupDomain(ctx, domain))
 }
 
 func (c *Client) GetAutnumRDAPServers(ctx context.Context, asn string) ([]*url.URL, error) {
-	var err error
-	if c.registries[ASN] == nil {
-		c.registries[ASN], err = c.FetchRegistryByType(ctx, ASN, true)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch ASN service registry: %w", err)
+	return urlsOf(c.LookupAutnum(ctx, asn))
+}
+
+// GetEntityRDAPServers returns the RDAP servers for an entity handle, using
+// the object tag suffix of the handle (e.g. "ABC123-ARIN").
+func (c *Client) GetEntityRDAPServers(ctx context.Context, handle string) ([]*url.URL, error) {
+	return urlsOf(c.LookupEntity(ctx, handle))
+}
+
+func (c *Client) GetIPAddressRDAPServers(ctx context.Context, ip string) ([]*url.URL, error) {
+	return urlsOf(c.LookupIPAddress(ctx, ip))
+}
+
+// GetIPAddrRDAPServers is like GetIPAddressRDAPServers for a netip.Addr.
+func (c *Client) GetIPAddrRDAPServers(ctx context.Context, addr netip.Addr) ([]*url.URL, error) {
+	return urlsOf(c.LookupIPAddr(ctx, addr))
+}
+
+// GetIPPrefixRDAPServers returns the RDAP servers for a network. The servers
+// of a bootstrap entry are only returned if it covers the whole of prefix.
+func (c *Client) GetIPPrefixRDAPServers(ctx context.Context, prefix netip.Prefix) ([]*url.URL, error) {
+	return urlsOf(c.LookupIPPrefix(ctx, prefix))
+}
+
+// LookupDomain is like GetDomainRDAPServers but also reports the source of
+// the servers. The supplementary registry, if configured, is consulted when
+// the TLD is not in the IANA registry.
+//
+// Special-use domain names (e.g. ".local", ".onion") are answered without
+// fetching the registry, with a *SpecialPurposeError.
+func (c *Client) LookupDomain(ctx context.Context, domain string) (*Result, error) {
+	getDNSServers := func(r *Registry) ([]*url.URL, error) {
+		return r.getDNSServers(domain)
+	}
+
+	result, err := c.lookup(ctx, DNS, domain, LookupSpecialUseDomain(domain), getDNSServers)
+	var spErr *SpecialPurposeError
+	if !errors.Is(err, ErrRDAPNotSupported) || errors.As(err, &spErr) {
+		return result, err
+	}
+
+	if supplementary := c.Supplementary(); supplementary != nil {
+		if urls, suppErr := getDNSServers(supplementary); suppErr == nil {
+			return &Result{URLs: urls, Source: SourceSupplementary}, nil
 		}
 	}
-	return c.registries[ASN].getASNServers(asn)
+	return nil, err
 }
 
-func (c *Client) GetIPAddressRDAPServers(ctx context.Context, ip string) ([]*url.URL, error) {
-	var err error
-	ipAddress := net.ParseIP(ip)
-	if ipAddress == nil {
+// LookupAutnum is like GetAutnumRDAPServers but also reports the source of
+// the servers.
+func (c *Client) LookupAutnum(ctx context.Context, asn string) (*Result, error) {
+	return c.lookup(ctx, ASN, asn, nil, func(r *Registry) ([]*url.URL, error) {
+		return r.getASNServers(asn)
+	})
+}
+
+// LookupEntity is like GetEntityRDAPServers but also reports the source of
+// the servers.
+func (c *Client) LookupEntity(ctx context.Context, handle string) (*Result, error) {
+	return c.lookup(ctx, ObjectTags, handle, nil, func(r *Registry) ([]*url.URL, error) {
+		return r.getObjectTagServers(handle)
+	})
+}
+
+// LookupIPAddress is like GetIPAddressRDAPServers but also reports the source
+// of the servers.
+//
+// Special-purpose addresses (e.g. private-use or documentation blocks) are
+// answered without fetching the registry, with a *SpecialPurposeError.
+// IPv4-mapped IPv6 addresses are looked up in the IPv4 registry.
+func (c *Client) LookupIPAddress(ctx context.Context, ip string) (*Result, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
 		return nil, fmt.Errorf("input %s is not an IP Address", ip)
 	}
-	// IPv4 address
-	if ipAddress.To4() != nil {
-		if c.registries[IPv4] == nil {
-			c.registries[IPv4], err = c.FetchRegistryByType(ctx, IPv4, true)
-			if err != nil {
-				return nil, fmt.Errorf("failed to fetch IPv4 service registry: %w", err)
-			}
-		}
-		return c.registries[IPv4].getNetServers(ipAddress)
-	} else { // IPv6 address
-		if c.registries[IPv6] == nil {
-			c.registries[IPv6], err = c.FetchRegistryByType(ctx, IPv6, true)
-			if err != nil {
-				return nil, fmt.Errorf("failed to fetch IPv6 service registry: %w", err)
-			}
+	return c.LookupIPAddr(ctx, addr)
+}
+
+// LookupIPAddr is like LookupIPAddress for a netip.Addr.
+func (c *Client) LookupIPAddr(ctx context.Context, addr netip.Addr) (*Result, error) {
+	if !addr.IsValid() {
+		return nil, fmt.Errorf("invalid IP Address")
+	}
+	addr = addr.WithZone("")
+	return c.LookupIPPrefix(ctx, netip.PrefixFrom(addr, addr.BitLen()))
+}
+
+// LookupIPPrefix is like GetIPPrefixRDAPServers but also reports the source
+// of the servers.
+//
+// A prefix inside a special-purpose block is answered without fetching the
+// registry, with a *SpecialPurposeError. IPv4-mapped IPv6 prefixes are looked
+// up in the IPv4 registry.
+func (c *Client) LookupIPPrefix(ctx context.Context, prefix netip.Prefix) (*Result, error) {
+	if !prefix.IsValid() {
+		return nil, fmt.Errorf("invalid IP prefix")
+	}
+	query := prefix.String()
+	prefix = unmapPrefix(prefix.Masked())
+
+	var special *SpecialPurpose
+	if sp := LookupSpecialPurposeAddress(prefix.Addr()); sp != nil && sp.Prefix.Bits() <= prefix.Bits() {
+		special = sp
+	}
+
+	regType := IPv6
+	if prefix.Addr().Is4() {
+		regType = IPv4
+	}
+	return c.lookup(ctx, regType, query, special, func(r *Registry) ([]*url.URL, error) {
+		return r.getNetServers(prefix)
+	})
+}
+
+// lookup runs a registry lookup against the overrides of regType and then,
+// if none of them matched, against the registry itself. If special is non-nil
+// the query is special-purpose, and only overrides can supply servers for it.
+func (c *Client) lookup(ctx context.Context, regType RegistryType, query string, special *SpecialPurpose, lookup func(*Registry) ([]*url.URL, error)) (*Result, error) {
+	if urls, ok, err := c.lookupOverride(regType, lookup); ok || err != nil {
+		if err != nil {
+			return nil, err
 		}
-		return c.registries[IPv6].getNetServers(ipAddress)
+		return &Result{URLs: urls, Source: SourceOverride}, nil
+	}
+
+	if special != nil {
+		return nil, &SpecialPurposeError{Query: query, SpecialPurpose: special}
+	}
+
+	registry, err := c.loadRegistry(ctx, regType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s service registry: %w", regType.String(), err)
+	}
+
+	urls, err := lookup(registry)
+	if err != nil {
+		return nil, err
 	}
+	return &Result{URLs: urls, Source: SourceIANA}, nil
+}
 
+func urlsOf(result *Result, err error) ([]*url.URL, error) {
+	if err != nil {
+		return nil, err
+	}
+	return result.URLs, nil
 }
@@ -0,0 +1,70 @@
+package bootstrap
+
+import (
+	"net/url"
+	"slices"
+	"sort"
+)
+
+// A ServiceChange describes one registry key whose servers differ between two
+// registries. OldURLs is nil for an added key and NewURLs is nil for a removed
+// one.
+type ServiceChange struct {
+	Key     string
+	OldURLs []*url.URL
+	NewURLs []*url.URL
+}
+
+// A RegistryDiff lists the differences between two publications of a registry.
+// Each list is sorted by key.
+type RegistryDiff struct {
+	OldPublication string
+	NewPublication string
+
+	Added   []ServiceChange
+	Removed []ServiceChange
+	Changed []ServiceChange
+}
+
+// Empty reports whether the two registries map every key to the same servers.
+func (d *RegistryDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Diff compares the services of two registries of the same type. A key whose
+// servers were reordered is reported as changed, as the order expresses the
+// preference between servers. A nil registry is treated as empty.
+func Diff(old, new *Registry) *RegistryDiff {
+	if old == nil {
+		old = &Registry{}
+	}
+	if new == nil {
+		new = &Registry{}
+	}
+
+	diff := &RegistryDiff{
+		OldPublication: old.Publication,
+		NewPublication: new.Publication,
+	}
+
+	for key, oldURLs := range old.Services {
+		newURLs, ok := new.Services[key]
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, ServiceChange{Key: key, OldURLs: oldURLs})
+		case !slices.Equal(urlsToStrings(oldURLs), urlsToStrings(newURLs)):
+			diff.Changed = append(diff.Changed, ServiceChange{Key: key, OldURLs: oldURLs, NewURLs: newURLs})
+		}
+	}
+	for key, newURLs := range new.Services {
+		if _, ok := old.Services[key]; !ok {
+			diff.Added = append(diff.Added, ServiceChange{Key: key, NewURLs: newURLs})
+		}
+	}
+
+	for _, changes := range [][]ServiceChange{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(changes, func(i, j int) bool { return lessKey(changes[i].Key, changes[j].Key) })
+	}
+
+	return diff
+}
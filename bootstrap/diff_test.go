@@ -0,0 +1,61 @@
+package bootstrap
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiff(t *testing.T) {
+	old, err := LoadRegistry(strings.NewReader(`{
+  "publication": "2024-10-01T00:00:00Z",
+  "services": [
+    [["com", "net"], ["https://rdap.example.com/"]],
+    [["org"], ["https://rdap.example.org/"]],
+    [["info"], ["https://rdap.example.info/", "http://rdap.example.info/"]]
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+
+	new, err := LoadRegistry(strings.NewReader(`{
+  "publication": "2024-11-01T00:00:00Z",
+  "services": [
+    [["com", "net"], ["https://rdap.example.com/"]],
+    [["info"], ["http://rdap.example.info/", "https://rdap.example.info/"]],
+    [["xyz", "app"], ["https://rdap.example.xyz/"]]
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+
+	diff := Diff(old, new)
+
+	keys := func(changes []ServiceChange) []string {
+		var result []string
+		for _, c := range changes {
+			result = append(result, c.Key)
+		}
+		return result
+	}
+
+	if diff.OldPublication != "2024-10-01T00:00:00Z" || diff.NewPublication != "2024-11-01T00:00:00Z" {
+		t.Errorf("unexpected publications %s, %s", diff.OldPublication, diff.NewPublication)
+	}
+	if !cmp.Equal(keys(diff.Added), []string{"app", "xyz"}) {
+		t.Errorf("unexpected added keys %v", keys(diff.Added))
+	}
+	if !cmp.Equal(keys(diff.Removed), []string{"org"}) {
+		t.Errorf("unexpected removed keys %v", keys(diff.Removed))
+	}
+	if !cmp.Equal(keys(diff.Changed), []string{"info"}) {
+		t.Errorf("unexpected changed keys %v", keys(diff.Changed))
+	}
+
+	if !Diff(new, new).Empty() {
+		t.Error("expected no differences between a registry and itself")
+	}
+}
@@ -8,4 +8,5 @@ var (
 	ErrInvalidCIDR      = errors.New("invalid CIDR block")
 	ErrRDAPNotSupported = errors.New("RDAP server not found")
 	ErrInvalidASNRange  = errors.New("invalid ASN range")
+	ErrSpecialPurpose   = errors.New("special-purpose address or domain")
 )
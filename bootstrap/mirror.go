@@ -0,0 +1,37 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// MirrorRegistryTypes lists the registries written by WriteMirror.
+var MirrorRegistryTypes = []RegistryType{DNS, IPv4, IPv6, ASN, ObjectTags}
+
+// WriteMirror writes every registry to dir under its IANA file name
+// (dns.json, ipv4.json, ...), fetching registries which are not loaded yet.
+//
+// The directory can be served with http.FileServer, and its URL used as the
+// serviceRegistryIndexURL of other clients. Overrides and the supplementary
+// registry are not included.
+func (c *Client) WriteMirror(ctx context.Context, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("unable to create mirror directory: %w", err)
+	}
+
+	for _, regType := range MirrorRegistryTypes {
+		registry, err := c.loadRegistry(ctx, regType)
+		if err != nil {
+			return fmt.Errorf("failed to fetch %s service registry: %w", regType.String(), err)
+		}
+
+		path := filepath.Join(dir, filepath.Base(regType.ServiceRegistryIndexURL("/")))
+		if err = registry.WriteFile(path); err != nil {
+			return fmt.Errorf("unable to write registry %s: %w", regType.String(), err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package bootstrap
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AddOverride maps key to the given RDAP servers, taking precedence over the
+// IANA registries. The key is a domain suffix for DNS (e.g. "corp.internal"),
+// a CIDR block for IPv4 and IPv6 (e.g. "10.0.0.0/8"), an ASN range for ASN
+// (e.g. "64512-65534") and an object tag for ObjectTags.
+//
+// Overrides are consulted before the registry is fetched, so they also work
+// without access to the registry server.
+func (c *Client) AddOverride(regType RegistryType, key string, servers ...string) error {
+	if err := validateKey(regType, key); err != nil {
+		return err
+	}
+	if len(servers) == 0 {
+		return fmt.Errorf("no RDAP servers given for override %s", key)
+	}
+	urls, err := parseURLs(servers)
+	if err != nil {
+		return fmt.Errorf("failed to parse URLs: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.overrides == nil {
+		c.overrides = make(map[RegistryType]*Registry)
+	}
+	if c.overrides[regType] == nil {
+		c.overrides[regType] = &Registry{Services: make(map[string][]*url.URL)}
+	}
+	c.overrides[regType].Services[normalizeKey(regType, key)] = urls
+
+	return nil
+}
+
+// SetOverrides replaces all overrides of regType with the services of
+// registry, e.g. one loaded with LoadRegistryFile. Keys are normalized as by
+// AddOverride, registry itself is not modified. A nil registry removes the
+// overrides.
+func (c *Client) SetOverrides(regType RegistryType, registry *Registry) error {
+	if registry != nil {
+		services := make(map[string][]*url.URL, len(registry.Services))
+		for key, urls := range registry.Services {
+			if err := validateKey(regType, key); err != nil {
+				return err
+			}
+			services[normalizeKey(regType, key)] = urls
+		}
+		normalized := *registry
+		normalized.Services = services
+		registry = &normalized
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.overrides == nil {
+		c.overrides = make(map[RegistryType]*Registry)
+	}
+	c.overrides[regType] = registry
+
+	return nil
+}
+
+// SetRegistry replaces the loaded registry of regType, e.g. with one read by
+// LoadRegistry or LoadRegistryFile, so it is used without being fetched. The
+// registry is pinned: a refresher leaves it in place, only an explicit
+// FetchRegistryByType replaces it.
+func (c *Client) SetRegistry(regType RegistryType, registry *Registry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.registries[regType] = registry
+	delete(c.entries, regType)
+	c.origins[regType] = registryOrigin{pinned: true, loadedAt: time.Now()}
+}
+
+// override returns the overrides of regType, or nil if there are none.
+func (c *Client) override(regType RegistryType) *Registry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.overrides[regType]
+}
+
+// lookupOverride runs lookup against the overrides of regType. It reports
+// whether an override matched.
+func (c *Client) lookupOverride(regType RegistryType, lookup func(*Registry) ([]*url.URL, error)) ([]*url.URL, bool, error) {
+	registry := c.override(regType)
+	if registry == nil {
+		return nil, false, nil
+	}
+
+	urls, err := lookup(registry)
+	if errors.Is(err, ErrRDAPNotSupported) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return urls, true, nil
+}
+
+// normalizeKey returns key in the form lookups use: DNS keys are lower case
+// and without a trailing dot.
+func normalizeKey(regType RegistryType, key string) string {
+	if regType == DNS {
+		return strings.ToLower(strings.TrimSuffix(key, "."))
+	}
+	return key
+}
+
+// validateKey checks that key is a valid service key for regType.
+func validateKey(regType RegistryType, key string) error {
+	switch regType {
+	case DNS, ObjectTags:
+		if key == "" {
+			return fmt.Errorf("empty %s override key", regType.String())
+		}
+	case IPv4, IPv6:
+		prefix, err := netip.ParsePrefix(key)
+		if err != nil {
+			return fmt.Errorf("%s: %w", key, ErrInvalidCIDR)
+		}
+		if prefix.Addr().Is4() != (regType == IPv4) {
+			return fmt.Errorf("%s is not an %s CIDR block: %w", key, regType.String(), ErrInvalidCIDR)
+		}
+	case ASN:
+		rangeParts := strings.Split(key, "-")
+		if len(rangeParts) > 2 {
+			return fmt.Errorf("%s: %w", key, ErrInvalidASNRange)
+		}
+		for _, part := range rangeParts {
+			if _, err := strconv.ParseUint(part, 10, 32); err != nil {
+				return fmt.Errorf("%s: %w", key, ErrInvalidASNRange)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestOverrides(t *testing.T) {
+	// overrides must work without fetching the IANA registries
+	client := NewBootstrapClient(nil, "")
+	ctx := context.Background()
+
+	if err := client.AddOverride(DNS, "corp.internal", "https://rdap.corp.internal/"); err != nil {
+		t.Fatalf("failed to add DNS override: %v", err)
+	}
+	if err := client.AddOverride(IPv4, "10.0.0.0/8", "https://ipam.corp.internal/rdap/"); err != nil {
+		t.Fatalf("failed to add IPv4 override: %v", err)
+	}
+	if err := client.AddOverride(IPv4, "10.20.0.0/16", "https://ipam-eu.corp.internal/rdap/"); err != nil {
+		t.Fatalf("failed to add IPv4 override: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		lookup   func() (string, error)
+		expected string
+	}{
+		{
+			name:     "Domain suffix",
+			lookup:   firstURL(client.GetDomainRDAPServers, ctx, "host.eng.corp.internal"),
+			expected: "https://rdap.corp.internal/",
+		},
+		{
+			name:     "IPv4 network",
+			lookup:   firstURL(client.GetIPAddressRDAPServers, ctx, "10.1.2.3"),
+			expected: "https://ipam.corp.internal/rdap/",
+		},
+		{
+			name:     "Most specific IPv4 network",
+			lookup:   firstURL(client.GetIPAddressRDAPServers, ctx, "10.20.1.1"),
+			expected: "https://ipam-eu.corp.internal/rdap/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.lookup()
+			if err != nil {
+				t.Fatalf("lookup failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestSetOverridesMixedCase(t *testing.T) {
+	registry, err := LoadRegistry(strings.NewReader(`{
+  "version": "1.0",
+  "services": [[["Corp.Internal."], ["https://rdap.corp.internal/"]]]
+}`))
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+
+	client := NewBootstrapClient(nil, "")
+	if err := client.SetOverrides(DNS, registry); err != nil {
+		t.Fatalf("failed to set overrides: %v", err)
+	}
+
+	result, err := firstURL(client.GetDomainRDAPServers, context.Background(), "host.CORP.internal")()
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if expected := "https://rdap.corp.internal/"; result != expected {
+		t.Errorf("Expected %s, got %s", expected, result)
+	}
+	if _, ok := registry.Services["Corp.Internal."]; !ok {
+		t.Error("expected the registry passed to SetOverrides to be unchanged")
+	}
+}
+
+func TestAddOverrideInvalidKey(t *testing.T) {
+	client := NewBootstrapClient(nil, "")
+
+	if err := client.AddOverride(IPv4, "2001:db8::/32", "https://rdap.example/"); !errors.Is(err, ErrInvalidCIDR) {
+		t.Errorf("expected ErrInvalidCIDR for an IPv6 block, got %v", err)
+	}
+	if err := client.AddOverride(ASN, "64512-x", "https://rdap.example/"); !errors.Is(err, ErrInvalidASNRange) {
+		t.Errorf("expected ErrInvalidASNRange, got %v", err)
+	}
+	if err := client.AddOverride(DNS, "example", "rdap.example"); err == nil {
+		t.Error("expected an error for a URL without scheme")
+	}
+}
+
+func TestLoadRegistry(t *testing.T) {
+	registry, err := LoadRegistry(strings.NewReader(testDNSRegistry))
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+
+	client := NewBootstrapClient(nil, "")
+	client.SetRegistry(DNS, registry)
+
+	servers, err := client.GetDomainRDAPServers(context.Background(), "example.net")
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if servers[0].String() != "https://rdap.example.com/" {
+		t.Errorf("unexpected server %s", servers[0])
+	}
+}
+
+func TestLookupIPPrefix(t *testing.T) {
+	client := NewBootstrapClient(nil, "", WithSnapshot())
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		hasError bool
+	}{
+		{
+			name:     "Prefix inside an entry",
+			input:    "8.8.8.0/24",
+			expected: "https://rdap.arin.net/registry/",
+		},
+		{
+			name:     "IPv4-mapped prefix",
+			input:    "::ffff:8.8.8.0/120",
+			expected: "https://rdap.arin.net/registry/",
+		},
+		{
+			name:     "Prefix spanning entries",
+			input:    "8.0.0.0/7",
+			hasError: true,
+		},
+		{
+			name:     "Documentation prefix",
+			input:    "2001:db8:1::/48",
+			hasError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.LookupIPPrefix(ctx, netip.MustParsePrefix(tt.input))
+			if (err != nil) != tt.hasError {
+				t.Fatalf("Expected error: %v, got: %v", tt.hasError, err)
+			}
+			if err == nil && result.URLs[0].String() != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result.URLs[0])
+			}
+		})
+	}
+}
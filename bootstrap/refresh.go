@@ -0,0 +1,209 @@
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRefreshInterval is used by a refresher when neither an interval
+	// is configured nor the registry server sent caching headers.
+	DefaultRefreshInterval = 24 * time.Hour
+
+	// minRefreshInterval bounds header-derived intervals and retries after a
+	// failed refresh, so a misbehaving server cannot cause a busy loop.
+	minRefreshInterval = time.Minute
+)
+
+// RefreshConfig configures a refresher started with Client.StartRefresher.
+type RefreshConfig struct {
+	// Interval between two refreshes of a registry. If zero, the schedule is
+	// derived from the Cache-Control and Expires headers of each registry,
+	// falling back to DefaultRefreshInterval.
+	Interval time.Duration
+
+	// OnChange, if set, is called after a registry with a different
+	// publication date has been swapped in. old is nil for a registry which
+	// was not loaded before.
+	OnChange func(regType RegistryType, old, new *Registry)
+
+	// OnError, if set, is called when a refresh fails. The previously loaded
+	// registry stays in use.
+	OnError func(regType RegistryType, err error)
+}
+
+// StartRefresher starts a goroutine which periodically re-fetches every loaded
+// registry and atomically replaces it when its publication date changes.
+// Registries which have not been loaded yet are left to be fetched on demand,
+// and registries pinned with SetRegistry are never replaced.
+//
+// The refresher stops when ctx is done; the returned channel is closed once it
+// has exited.
+func (c *Client) StartRefresher(ctx context.Context, cfg RefreshConfig) <-chan struct{} {
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		retries := make(map[RegistryType]time.Time)
+		timer := time.NewTimer(c.nextRefresh(cfg, retries, time.Now()))
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+			}
+
+			c.refreshDue(ctx, cfg, retries)
+			timer.Reset(c.nextRefresh(cfg, retries, time.Now()))
+		}
+	}()
+
+	return done
+}
+
+// refreshDue re-fetches every loaded registry whose refresh time has passed.
+func (c *Client) refreshDue(ctx context.Context, cfg RefreshConfig, retries map[RegistryType]time.Time) {
+	now := time.Now()
+	for regType, due := range c.refreshSchedule(cfg, retries) {
+		if due.After(now) {
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		delete(retries, regType)
+		if err := c.refresh(ctx, regType, cfg.OnChange); err != nil {
+			retries[regType] = now.Add(retryInterval(cfg))
+			if cfg.OnError != nil {
+				cfg.OnError(regType, err)
+			}
+		}
+	}
+}
+
+// refresh fetches the registry for regType and swaps it in if its
+// publication date differs from the loaded one.
+func (c *Client) refresh(ctx context.Context, regType RegistryType, onChange func(RegistryType, *Registry, *Registry)) error {
+	registry, entry, err := c.fetch(ctx, regType)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	if c.origins[regType].pinned {
+		// pinned with SetRegistry while the fetch was in progress
+		c.mu.Unlock()
+		return nil
+	}
+	old := c.registries[regType]
+	changed := old == nil || old.Publication != registry.Publication
+	if changed {
+		c.registries[regType] = registry
+	}
+	c.entries[regType] = entry
+	delete(c.origins, regType)
+	c.mu.Unlock()
+
+	if changed && onChange != nil {
+		onChange(regType, old, registry)
+	}
+	return nil
+}
+
+// refreshSchedule returns the next refresh time of every loaded registry.
+// Registries pinned with SetRegistry are not refreshed.
+func (c *Client) refreshSchedule(cfg RefreshConfig, retries map[RegistryType]time.Time) map[RegistryType]time.Time {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	schedule := make(map[RegistryType]time.Time, len(c.registries))
+	for regType := range c.registries {
+		if retry, ok := retries[regType]; ok {
+			schedule[regType] = retry
+			continue
+		}
+
+		origin := c.origins[regType]
+		if origin.pinned {
+			continue
+		}
+
+		entry := c.entries[regType]
+		if entry == nil {
+			// registry was not fetched over HTTP, e.g. loaded from the
+			// snapshot, it is due an interval after it was loaded
+			entry = &cacheEntry{FetchedAt: origin.loadedAt}
+		}
+
+		switch {
+		case cfg.Interval > 0:
+			schedule[regType] = entry.FetchedAt.Add(cfg.Interval)
+		case !entry.Expires.IsZero():
+			earliest := entry.FetchedAt.Add(minRefreshInterval)
+			if entry.Expires.Before(earliest) {
+				schedule[regType] = earliest
+			} else {
+				schedule[regType] = entry.Expires
+			}
+		default:
+			schedule[regType] = entry.FetchedAt.Add(DefaultRefreshInterval)
+		}
+	}
+	return schedule
+}
+
+// nextRefresh returns how long to wait until the next registry is due. The
+// wait is capped so registries loaded in the meantime are picked up.
+func (c *Client) nextRefresh(cfg RefreshConfig, retries map[RegistryType]time.Time, now time.Time) time.Duration {
+	wait := minRefreshInterval
+	for _, due := range c.refreshSchedule(cfg, retries) {
+		if d := due.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	if wait < 0 {
+		wait = 0
+	}
+	return wait
+}
+
+func retryInterval(cfg RefreshConfig) time.Duration {
+	if cfg.Interval > 0 && cfg.Interval < minRefreshInterval {
+		return cfg.Interval
+	}
+	return minRefreshInterval
+}
+
+// expiresFromHeaders derives the expiry time of a response from its
+// Cache-Control max-age directive or, failing that, its Expires header. The
+// zero time is returned if neither is present.
+func expiresFromHeaders(header http.Header, now time.Time) time.Time {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			return now
+		case strings.HasPrefix(directive, "max-age="):
+			if seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil {
+				return now.Add(time.Duration(seconds) * time.Second)
+			}
+		}
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		if t, err := http.ParseTime(expires); err == nil {
+			return t
+		}
+		// an invalid Expires header means the response is already expired
+		return now
+	}
+
+	return time.Time{}
+}
@@ -0,0 +1,134 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRefresherSwapsChangedRegistry(t *testing.T) {
+	var publication atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"publication": "2024-10-0%dT00:00:00Z", "services": [[["com"], ["https://rdap.example.com/"]]]}`, publication.Load()+1)
+	}))
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	client := NewBootstrapClient(mockServer.Client(), mockServer.URL+"/")
+	if _, err := client.FetchRegistryByType(ctx, DNS, true); err != nil {
+		t.Fatalf("initial fetch failed: %v", err)
+	}
+
+	changes := make(chan *Registry, 1)
+	done := client.StartRefresher(ctx, RefreshConfig{
+		Interval: 10 * time.Millisecond,
+		OnChange: func(regType RegistryType, old, new *Registry) {
+			changes <- new
+		},
+	})
+
+	publication.Store(1)
+
+	select {
+	case registry := <-changes:
+		if registry.Publication != "2024-10-02T00:00:00Z" {
+			t.Errorf("unexpected publication %q", registry.Publication)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("refresher did not report a change")
+	}
+
+	if got := client.registry(DNS).Publication; got != "2024-10-02T00:00:00Z" {
+		t.Errorf("registry was not swapped, publication is %q", got)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("refresher did not stop after cancellation")
+	}
+}
+
+func TestExpiresFromHeaders(t *testing.T) {
+	now := time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		expected time.Time
+	}{
+		{
+			name:     "max-age",
+			header:   http.Header{"Cache-Control": {"public, max-age=3600"}},
+			expected: now.Add(time.Hour),
+		},
+		{
+			name:     "Expires",
+			header:   http.Header{"Expires": {"Wed, 02 Oct 2024 00:00:00 GMT"}},
+			expected: now.Add(24 * time.Hour),
+		},
+		{
+			name:     "no-cache",
+			header:   http.Header{"Cache-Control": {"no-cache"}},
+			expected: now,
+		},
+		{
+			name:     "No headers",
+			header:   http.Header{},
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := expiresFromHeaders(tt.header, now); !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestRefresherKeepsPinnedRegistry(t *testing.T) {
+	var requests atomic.Int32
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprint(w, `{"publication": "2024-10-02T00:00:00Z", "services": [[["com"], ["https://rdap.example.com/"]]]}`)
+	}))
+	defer mockServer.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	client := NewBootstrapClient(mockServer.Client(), mockServer.URL+"/")
+	pinned := &Registry{Publication: "2024-10-01T00:00:00Z"}
+	client.SetRegistry(DNS, pinned)
+
+	done := client.StartRefresher(ctx, RefreshConfig{
+		Interval: 10 * time.Millisecond,
+		OnChange: func(regType RegistryType, old, new *Registry) {
+			t.Errorf("unexpected change of registry %s", regType)
+		},
+	})
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	<-done
+
+	if client.registry(DNS) != pinned || requests.Load() != 0 {
+		t.Errorf("pinned registry was refreshed with %d requests", requests.Load())
+	}
+}
+
+func TestRefreshScheduleOfSnapshot(t *testing.T) {
+	client := NewBootstrapClient(http.DefaultClient, "", WithSnapshot())
+
+	schedule := client.refreshSchedule(RefreshConfig{Interval: time.Hour}, nil)
+	for _, regType := range SnapshotRegistryTypes {
+		if due := schedule[regType]; time.Until(due) < 59*time.Minute {
+			t.Errorf("expected snapshot registry %s to be due in an hour, got %s", regType, due)
+		}
+	}
+}
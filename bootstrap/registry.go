@@ -3,8 +3,11 @@ package bootstrap
 import (
 	"encoding/json"
 	"fmt"
-	"net"
+	"io"
+	"net/netip"
 	"net/url"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -19,6 +22,7 @@ const (
 	IPv4
 	IPv6
 	ASN
+	ObjectTags
 )
 
 func (r RegistryType) String() string {
@@ -31,6 +35,8 @@ func (r RegistryType) String() string {
 		return "ipv6"
 	case ASN:
 		return "asn"
+	case ObjectTags:
+		return "object-tags"
 	default:
 		panic("Unknown RegistryType")
 	}
@@ -47,8 +53,10 @@ func (r *RegistryType) Set(value string) error {
 		*r = IPv6
 	case "asn":
 		*r = ASN
+	case "object-tags":
+		*r = ObjectTags
 	default:
-		return fmt.Errorf("invalid registry-type %s, must be one of: dns, ipv4, ipv6, asn", value)
+		return fmt.Errorf("invalid registry-type %s, must be one of: dns, ipv4, ipv6, asn, object-tags", value)
 	}
 	return nil
 }
@@ -67,6 +75,8 @@ func (r RegistryType) ServiceRegistryIndexURL(baseURL string) string {
 		return baseURL + "ipv6.json"
 	case ASN:
 		return baseURL + "asn.json"
+	case ObjectTags:
+		return baseURL + "object-tags.json"
 	default:
 		panic("Unknown RegistryType")
 	}
@@ -77,6 +87,36 @@ type Registry struct {
 	Publication string                `json:"publication"`
 	Description string                `json:"description"`
 	Services    map[string][]*url.URL `json:"services"`
+
+	// Contacts holds the registrant contacts of each object tag. It is only
+	// populated for the object tags registry (RFC 8521), whose services carry
+	// a contact list in front of the tags.
+	Contacts map[string][]string `json:"-"`
+}
+
+// LoadRegistry reads a registry in the IANA bootstrap file format from r.
+func LoadRegistry(r io.Reader) (*Registry, error) {
+	var registry Registry
+	if err := json.NewDecoder(r).Decode(&registry); err != nil {
+		return nil, fmt.Errorf("unable to parse registry: %w", err)
+	}
+	return &registry, nil
+}
+
+// LoadRegistryFile reads a registry in the IANA bootstrap file format from the
+// file at path.
+func LoadRegistryFile(path string) (*Registry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	registry, err := LoadRegistry(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return registry, nil
 }
 
 func (r *Registry) UnmarshalJSON(data []byte) error {
@@ -101,70 +141,167 @@ func (r *Registry) UnmarshalJSON(data []byte) error {
 	r.Services = make(map[string][]*url.URL)
 
 	for _, service := range temp.Services {
+		var contacts []string
+		// object tags services are [contacts, tags, urls] rather than [keys, urls]
+		if len(service) == 3 {
+			contacts, service = service[0], service[1:]
+			if r.Contacts == nil {
+				r.Contacts = make(map[string][]string)
+			}
+		}
+		if len(service) != 2 {
+			return fmt.Errorf("invalid service entry with %d elements", len(service))
+		}
+
 		for _, key := range service[0] {
 			parsedURL, err := parseURLs(service[1])
 			if err != nil {
 				return fmt.Errorf("failed to parse URLs: %w", err)
 			}
 			r.Services[key] = parsedURL
+			if contacts != nil {
+				r.Contacts[key] = contacts
+			}
 		}
 	}
 
 	return nil
 }
 
-func (r *Registry) MarshalJSON() ([]byte, error) {
-	if len(r.Services) == 0 {
-		return json.Marshal("")
-	}
-
-	// Create a temporary structure to hold the formatted services
-	temp := struct {
-		Services [][][]string `json:"services"`
-	}{
-		Services: make([][][]string, 0, len(r.Services)),
+// MarshalJSON encodes the registry in the IANA bootstrap file format. Keys
+// sharing the same servers (and contacts, for object tags) are grouped into
+// one service, and keys and services are sorted, so the output is stable.
+func (r Registry) MarshalJSON() ([]byte, error) {
+	type group struct {
+		keys     []string
+		contacts []string
+		urls     []string
 	}
 
-	// Iterate over the Services map
+	groups := make(map[string]*group)
 	for key, urls := range r.Services {
-		// Convert []*url.URL back to a list of URL strings
 		urlStrs := urlsToStrings(urls)
+		contacts := r.Contacts[key]
 
-		// Append the key and URLs to the temp.Services slice in the expected format
-		temp.Services = append(temp.Services, [][]string{
-			{key},   // First slice contains the key
-			urlStrs, // Second slice contains URLs
-		})
+		// keys are grouped by their URLs and contacts, in order
+		id := strings.Join(contacts, "\n") + "\x00" + strings.Join(urlStrs, "\n")
+		if groups[id] == nil {
+			groups[id] = &group{contacts: contacts, urls: urlStrs}
+		}
+		groups[id].keys = append(groups[id].keys, key)
+	}
+
+	sorted := make([]*group, 0, len(groups))
+	for _, g := range groups {
+		sort.Slice(g.keys, func(i, j int) bool { return lessKey(g.keys[i], g.keys[j]) })
+		sorted = append(sorted, g)
 	}
+	sort.Slice(sorted, func(i, j int) bool { return lessKey(sorted[i].keys[0], sorted[j].keys[0]) })
 
-	// Marshal the entire struct into JSON
-	return json.Marshal(temp)
+	services := make([][][]string, 0, len(sorted))
+	for _, g := range sorted {
+		if r.Contacts != nil {
+			if g.contacts == nil {
+				g.contacts = []string{}
+			}
+			services = append(services, [][]string{g.contacts, g.keys, g.urls})
+		} else {
+			services = append(services, [][]string{g.keys, g.urls})
+		}
+	}
+
+	return json.Marshal(struct {
+		Description string       `json:"description"`
+		Publication string       `json:"publication"`
+		Services    [][][]string `json:"services"`
+		Version     string       `json:"version"`
+	}{
+		Description: r.Description,
+		Publication: r.Publication,
+		Services:    services,
+		Version:     r.Version,
+	})
+}
+
+// WriteFile writes the registry to path in the IANA bootstrap file format.
+func (r *Registry) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, append(data, '\n'))
+}
+
+// lessKey orders service keys: CIDR blocks by address and length, ASN ranges
+// numerically and anything else (domains, object tags) alphabetically.
+func lessKey(a, b string) bool {
+	if pa, err := netip.ParsePrefix(a); err == nil {
+		if pb, err := netip.ParsePrefix(b); err == nil {
+			if c := pa.Addr().Compare(pb.Addr()); c != 0 {
+				return c < 0
+			}
+			return pa.Bits() < pb.Bits()
+		}
+	}
+	if na, err := strconv.ParseUint(strings.SplitN(a, "-", 2)[0], 10, 32); err == nil {
+		if nb, err := strconv.ParseUint(strings.SplitN(b, "-", 2)[0], 10, 32); err == nil && na != nb {
+			return na < nb
+		}
+	}
+	return a < b
 }
 
-func (r *Registry) getNetServers(ipAddr net.IP) ([]*url.URL, error) {
-	for cidr := range r.Services {
+// getNetServers returns the servers of the most specific CIDR block
+// containing the whole of prefix. An address is looked up as a /32 or /128.
+func (r *Registry) getNetServers(prefix netip.Prefix) ([]*url.URL, error) {
+	var match []*url.URL
+	bestLen := -1
+	for cidr, urls := range r.Services {
 		// Parse the CIDR block
-		_, ipNet, err := net.ParseCIDR(cidr)
+		block, err := netip.ParsePrefix(cidr)
 		if err != nil {
 			return nil, ErrInvalidCIDR
 		}
 
-		// Check if the IP is in the CIDR range
-		if ipNet.Contains(ipAddr) {
-			return r.Services[cidr], nil
+		// Check if the prefix is in the CIDR range
+		if block.Bits() <= prefix.Bits() && block.Contains(prefix.Addr()) && block.Bits() > bestLen {
+			match, bestLen = urls, block.Bits()
 		}
 	}
-	return nil, ErrRDAPNotSupported
+	if match == nil {
+		return nil, ErrRDAPNotSupported
+	}
+	return match, nil
 }
 
+// getDNSServers returns the servers of the longest entry matching the labels
+// at the end of domain, so "corp.example" takes precedence over "example".
 func (r *Registry) getDNSServers(domain string) ([]*url.URL, error) {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
 	parts := strings.Split(domain, ".")
 	tld := parts[len(parts)-1]
 
-	if r.Services[tld] == nil {
-		return nil, fmt.Errorf("tld %s not supported: %w", tld, ErrRDAPNotSupported)
+	for i := range parts {
+		if urls := r.Services[strings.Join(parts[i:], ".")]; urls != nil {
+			return urls, nil
+		}
+	}
+	return nil, fmt.Errorf("tld %s not supported: %w", tld, ErrRDAPNotSupported)
+}
+
+func (r *Registry) getObjectTagServers(handle string) ([]*url.URL, error) {
+	sep := strings.LastIndex(handle, "-")
+	if sep == -1 {
+		return nil, fmt.Errorf("handle %s has no object tag: %w", handle, ErrRDAPNotSupported)
+	}
+	tag := handle[sep+1:]
+
+	for key, urls := range r.Services {
+		if strings.EqualFold(key, tag) {
+			return urls, nil
+		}
 	}
-	return r.Services[tld], nil
+	return nil, fmt.Errorf("object tag %s not supported: %w", tag, ErrRDAPNotSupported)
 }
 
 func (r *Registry) getASNServers(input string) ([]*url.URL, error) {
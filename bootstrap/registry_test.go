@@ -0,0 +1,84 @@
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegistryMarshalRoundTrip(t *testing.T) {
+	for _, regType := range SnapshotRegistryTypes {
+		t.Run(regType.String(), func(t *testing.T) {
+			registry, err := Snapshot(regType)
+			if err != nil {
+				t.Fatalf("failed to load snapshot: %v", err)
+			}
+
+			first, err := json.Marshal(registry)
+			if err != nil {
+				t.Fatalf("failed to marshal registry: %v", err)
+			}
+			second, err := json.Marshal(registry)
+			if err != nil {
+				t.Fatalf("failed to marshal registry: %v", err)
+			}
+			if !bytes.Equal(first, second) {
+				t.Error("marshalling the same registry twice gave different output")
+			}
+
+			var decoded Registry
+			if err = json.Unmarshal(first, &decoded); err != nil {
+				t.Fatalf("failed to unmarshal registry: %v", err)
+			}
+
+			if decoded.Version != registry.Version || decoded.Publication != registry.Publication || decoded.Description != registry.Description {
+				t.Errorf("metadata was not preserved: %+v", decoded)
+			}
+			if !cmp.Equal(servicesOf(&decoded), servicesOf(registry)) {
+				t.Errorf("services were not preserved: %s", cmp.Diff(servicesOf(registry), servicesOf(&decoded)))
+			}
+			if !cmp.Equal(decoded.Contacts, registry.Contacts) {
+				t.Errorf("contacts were not preserved: %s", cmp.Diff(registry.Contacts, decoded.Contacts))
+			}
+		})
+	}
+}
+
+func TestWriteMirror(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	client := NewBootstrapClient(nil, "", WithSnapshot())
+	if err := client.WriteMirror(ctx, dir); err != nil {
+		t.Fatalf("failed to write mirror: %v", err)
+	}
+
+	mirror := httptest.NewServer(http.StripPrefix("/rdap/", http.FileServer(http.Dir(dir))))
+	defer mirror.Close()
+
+	mirrored := NewBootstrapClient(mirror.Client(), mirror.URL+"/rdap/")
+	if err := mirrored.FetchAllRegistries(ctx); err != nil {
+		t.Fatalf("failed to fetch registries from mirror: %v", err)
+	}
+
+	servers, err := mirrored.GetEntityRDAPServers(ctx, "GOGL-ARIN")
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	if servers[0].String() != "https://rdap.arin.net/registry/" {
+		t.Errorf("unexpected server %s", servers[0])
+	}
+}
+
+func servicesOf(r *Registry) map[string][]string {
+	services := make(map[string][]string, len(r.Services))
+	for key, urls := range r.Services {
+		services[key] = urlsToStrings(urls)
+	}
+	return services
+}
@@ -0,0 +1,210 @@
+package bootstrap
+
+import (
+	"net/netip"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// An ASNRange is an inclusive range of AS numbers.
+type ASNRange struct {
+	Start uint32
+	End   uint32
+}
+
+func (r ASNRange) String() string {
+	if r.Start == r.End {
+		return strconv.FormatUint(uint64(r.Start), 10)
+	}
+	return strconv.FormatUint(uint64(r.Start), 10) + "-" + strconv.FormatUint(uint64(r.End), 10)
+}
+
+// ServedResources lists everything the bootstrap registries assign to one
+// RDAP server.
+type ServedResources struct {
+	// BaseURL is the normalized base URL of the server, see NormalizeBaseURL.
+	BaseURL string
+
+	Domains    []string
+	IPv4       []netip.Prefix
+	IPv6       []netip.Prefix
+	ASNs       []ASNRange
+	ObjectTags []string
+}
+
+// A ReverseIndex maps RDAP servers to the domains, networks, AS numbers and
+// object tags the bootstrap registries assign to them.
+type ReverseIndex struct {
+	servers map[string]*ServedResources
+}
+
+// ReverseIndex builds a reverse index over the registries loaded by the
+// Client. Overrides and the supplementary registry are not included.
+func (c *Client) ReverseIndex() *ReverseIndex {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return NewReverseIndex(c.registries)
+}
+
+// NewReverseIndex builds a reverse index over registries. Keys which cannot
+// be parsed for their registry type are skipped.
+func NewReverseIndex(registries map[RegistryType]*Registry) *ReverseIndex {
+	ri := &ReverseIndex{servers: make(map[string]*ServedResources)}
+
+	for regType, registry := range registries {
+		if registry == nil {
+			continue
+		}
+		for key, urls := range registry.Services {
+			// a key is only listed once per server, even if the server is
+			// registered with both http and https
+			seen := make(map[string]bool)
+			for _, u := range urls {
+				baseURL := NormalizeBaseURL(u)
+				if seen[baseURL] {
+					continue
+				}
+				seen[baseURL] = true
+				ri.add(regType, key, ri.server(baseURL))
+			}
+		}
+	}
+
+	for _, served := range ri.servers {
+		served.sort()
+	}
+	return ri
+}
+
+// Lookup returns the resources served by the server at baseURL, or nil if the
+// registries do not reference it. baseURL is normalized before the lookup.
+func (ri *ReverseIndex) Lookup(baseURL string) *ServedResources {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return nil
+	}
+	return ri.servers[NormalizeBaseURL(u)]
+}
+
+// LookupHost merges the resources of every server on host, e.g. all the TLDs
+// an operator serves from per-TLD paths of one host.
+func (ri *ReverseIndex) LookupHost(host string) *ServedResources {
+	host = strings.ToLower(host)
+
+	var merged *ServedResources
+	for _, served := range ri.Servers() {
+		u, err := url.Parse(served.BaseURL)
+		if err != nil || u.Host != host {
+			continue
+		}
+		if merged == nil {
+			merged = &ServedResources{BaseURL: u.Scheme + "://" + u.Host + "/"}
+		}
+		merged.Domains = append(merged.Domains, served.Domains...)
+		merged.IPv4 = append(merged.IPv4, served.IPv4...)
+		merged.IPv6 = append(merged.IPv6, served.IPv6...)
+		merged.ASNs = append(merged.ASNs, served.ASNs...)
+		merged.ObjectTags = append(merged.ObjectTags, served.ObjectTags...)
+	}
+
+	if merged != nil {
+		merged.sort()
+	}
+	return merged
+}
+
+// Servers returns the resources of every server in the index, sorted by base
+// URL.
+func (ri *ReverseIndex) Servers() []*ServedResources {
+	result := make([]*ServedResources, 0, len(ri.servers))
+	for _, served := range ri.servers {
+		result = append(result, served)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].BaseURL < result[j].BaseURL })
+	return result
+}
+
+// NormalizeBaseURL returns the canonical form of an RDAP base URL used as key
+// of the reverse index: https scheme, lower case host without default port,
+// and a path ending in a slash. The http and https URLs of a server therefore
+// map to the same key.
+func NormalizeBaseURL(u *url.URL) string {
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host += ":" + port
+	}
+
+	path := u.Path
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	return "https://" + host + path
+}
+
+func (ri *ReverseIndex) server(baseURL string) *ServedResources {
+	served := ri.servers[baseURL]
+	if served == nil {
+		served = &ServedResources{BaseURL: baseURL}
+		ri.servers[baseURL] = served
+	}
+	return served
+}
+
+func (ri *ReverseIndex) add(regType RegistryType, key string, served *ServedResources) {
+	switch regType {
+	case DNS:
+		served.Domains = append(served.Domains, key)
+	case IPv4, IPv6:
+		prefix, err := netip.ParsePrefix(key)
+		if err != nil {
+			return
+		}
+		if prefix.Addr().Is4() {
+			served.IPv4 = append(served.IPv4, prefix)
+		} else {
+			served.IPv6 = append(served.IPv6, prefix)
+		}
+	case ASN:
+		if r, ok := parseASNRange(key); ok {
+			served.ASNs = append(served.ASNs, r)
+		}
+	case ObjectTags:
+		served.ObjectTags = append(served.ObjectTags, key)
+	}
+}
+
+func (s *ServedResources) sort() {
+	sort.Strings(s.Domains)
+	sort.Strings(s.ObjectTags)
+	for _, prefixes := range [][]netip.Prefix{s.IPv4, s.IPv6} {
+		sort.Slice(prefixes, func(i, j int) bool {
+			if c := prefixes[i].Addr().Compare(prefixes[j].Addr()); c != 0 {
+				return c < 0
+			}
+			return prefixes[i].Bits() < prefixes[j].Bits()
+		})
+	}
+	sort.Slice(s.ASNs, func(i, j int) bool { return s.ASNs[i].Start < s.ASNs[j].Start })
+}
+
+// parseASNRange parses an ASN registry key such as "64512-65534" or "2043".
+func parseASNRange(key string) (ASNRange, bool) {
+	rangeParts := strings.Split(key, "-")
+	if len(rangeParts) > 2 {
+		return ASNRange{}, false
+	}
+
+	start, err := strconv.ParseUint(rangeParts[0], 10, 32)
+	if err != nil {
+		return ASNRange{}, false
+	}
+	end := start
+	if len(rangeParts) == 2 {
+		if end, err = strconv.ParseUint(rangeParts[1], 10, 32); err != nil {
+			return ASNRange{}, false
+		}
+	}
+	return ASNRange{Start: uint32(start), End: uint32(end)}, true
+}
@@ -0,0 +1,42 @@
+package bootstrap
+
+import (
+	"net/netip"
+	"slices"
+	"testing"
+)
+
+func TestReverseIndex(t *testing.T) {
+	client := NewBootstrapClient(nil, "", WithSnapshot())
+	index := client.ReverseIndex()
+
+	// http and https URLs of the server are grouped together
+	arin := index.Lookup("http://RDAP.ARIN.NET/registry")
+	if arin == nil {
+		t.Fatal("expected the ARIN server to be indexed")
+	}
+	if arin.BaseURL != "https://rdap.arin.net/registry/" {
+		t.Errorf("unexpected base URL %s", arin.BaseURL)
+	}
+	if !slices.Contains(arin.IPv4, netip.MustParsePrefix("8.0.0.0/8")) {
+		t.Error("expected 8.0.0.0/8 to be served by ARIN")
+	}
+	if !slices.Contains(arin.IPv6, netip.MustParsePrefix("2600::/12")) {
+		t.Error("expected 2600::/12 to be served by ARIN")
+	}
+	if !slices.Contains(arin.ASNs, ASNRange{Start: 1, End: 1876}) {
+		t.Error("expected AS1-AS1876 to be served by ARIN")
+	}
+	if !slices.Equal(arin.ObjectTags, []string{"ARIN"}) {
+		t.Errorf("unexpected object tags %v", arin.ObjectTags)
+	}
+
+	verisign := index.LookupHost("rdap.verisign.com")
+	if verisign == nil || !slices.Equal(verisign.Domains, []string{"com", "net"}) {
+		t.Errorf("unexpected domains for rdap.verisign.com: %+v", verisign)
+	}
+
+	if index.Lookup("https://rdap.unknown.example/") != nil {
+		t.Error("expected no resources for an unknown server")
+	}
+}
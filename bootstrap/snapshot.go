@@ -0,0 +1,80 @@
+package bootstrap
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"time"
+)
+
+// The snapshot directory holds a copy of the IANA bootstrap registries, for
+// use when data.iana.org cannot be reached. To regenerate it from
+// data.iana.org:
+//
+//	go generate ./bootstrap
+//
+// snapshot/gen.go refuses registries with fewer entries than
+// SnapshotMinServices, such as a DNS registry of fewer than 1000 TLDs.
+
+//go:generate go run snapshot/gen.go -dst snapshot
+
+//go:embed snapshot/*.json
+var snapshotFS embed.FS
+
+// SnapshotRegistryTypes lists the registries included in the embedded snapshot.
+var SnapshotRegistryTypes = []RegistryType{DNS, IPv4, IPv6, ASN, ObjectTags}
+
+// SnapshotMinServices is the least number of entries (TLDs, ranges or tags)
+// each registry of the snapshot must have, to catch truncated or trimmed
+// copies of the IANA files.
+var SnapshotMinServices = map[RegistryType]int{
+	DNS:        1000,
+	IPv4:       100,
+	IPv6:       10,
+	ASN:        50,
+	ObjectTags: 10,
+}
+
+// WithSnapshot loads every registry from the embedded snapshot when the Client
+// is created, so lookups work without network access. The registries can be
+// brought up to date with FetchAllRegistries, or by a refresher once its
+// interval has passed since the Client was created.
+func WithSnapshot() Option {
+	return func(c *Client) {
+		now := time.Now()
+		for _, regType := range SnapshotRegistryTypes {
+			registry, err := Snapshot(regType)
+			if err != nil {
+				// the embedded files are checked by the tests, this cannot happen
+				panic(err)
+			}
+			c.registries[regType] = registry
+			c.origins[regType] = registryOrigin{loadedAt: now}
+		}
+	}
+}
+
+// WithSnapshotFallback uses the embedded snapshot for a registry which cannot
+// be fetched and has no cached copy.
+func WithSnapshotFallback() Option {
+	return func(c *Client) {
+		c.snapshotFallback = true
+	}
+}
+
+// Snapshot returns the registry of regType from the embedded snapshot.
+func Snapshot(regType RegistryType) (*Registry, error) {
+	data, err := snapshotFile(regType)
+	if err != nil {
+		return nil, err
+	}
+	return parseRegistry(regType, data)
+}
+
+func snapshotFile(regType RegistryType) ([]byte, error) {
+	data, err := snapshotFS.ReadFile(path.Join("snapshot", regType.String()+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("registry %s is not in the snapshot: %w", regType.String(), err)
+	}
+	return data, nil
+}
@@ -0,0 +1,76 @@
+//go:build ignore
+
+// gen copies the IANA bootstrap files into the embedded snapshot, checking
+// that each of them parses as a bootstrap registry and is complete.
+//
+// Usage:
+//
+//	go run snapshot/gen.go -dst snapshot
+//	go run snapshot/gen.go -src /path/to/rdap -dst snapshot
+//
+// Without -src the files are downloaded from data.iana.org. -src may also be
+// the base URL of a mirror.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/perihwk/openrdap/bootstrap"
+)
+
+func main() {
+	src := flag.String("src", "https://data.iana.org/rdap/", "Directory or base URL containing the bootstrap files (dns.json, ipv4.json, ...)")
+	dst := flag.String("dst", "snapshot", "Snapshot directory to write to")
+	flag.Parse()
+
+	for _, regType := range bootstrap.SnapshotRegistryTypes {
+		name := regType.String() + ".json"
+
+		data, err := read(*src, name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+
+		var registry bootstrap.Registry
+		if err = json.Unmarshal(data, &registry); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s is not a valid bootstrap registry: %v\n", name, err)
+			os.Exit(1)
+		}
+		if min := bootstrap.SnapshotMinServices[regType]; len(registry.Services) < min {
+			fmt.Fprintf(os.Stderr, "Error: %s has %d entries, expected at least %d\n", name, len(registry.Services), min)
+			os.Exit(1)
+		}
+
+		if err = os.WriteFile(filepath.Join(*dst, name), data, 0o644); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+		fmt.Printf("%s: publication %s, %d entries\n", name, registry.Publication, len(registry.Services))
+	}
+}
+
+// read returns the file name from the directory or base URL src.
+func read(src, name string) ([]byte, error) {
+	if !strings.HasPrefix(src, "http://") && !strings.HasPrefix(src, "https://") {
+		return os.ReadFile(filepath.Join(src, name))
+	}
+
+	resp, err := http.Get(strings.TrimSuffix(src, "/") + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: server returned %s", name, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,114 @@
+package bootstrap
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestSnapshotRegistries(t *testing.T) {
+	for _, regType := range SnapshotRegistryTypes {
+		registry, err := Snapshot(regType)
+		if err != nil {
+			t.Fatalf("failed to load snapshot %s: %v", regType.String(), err)
+		}
+		if len(registry.Services) == 0 || registry.Publication == "" {
+			t.Errorf("snapshot %s is empty", regType.String())
+		}
+	}
+}
+
+func TestSnapshotComplete(t *testing.T) {
+	// a trimmed snapshot leaves air-gapped clients without servers for most
+	// TLDs; regenerate it with go generate ./bootstrap
+	for _, regType := range SnapshotRegistryTypes {
+		registry, err := Snapshot(regType)
+		if err != nil {
+			t.Fatalf("failed to load snapshot %s: %v", regType.String(), err)
+		}
+		if min := SnapshotMinServices[regType]; len(registry.Services) < min {
+			t.Errorf("snapshot %s has %d entries, expected at least %d", regType.String(), len(registry.Services), min)
+		}
+	}
+}
+
+func TestWithSnapshot(t *testing.T) {
+	// no registry server is needed, every lookup is answered from the snapshot
+	client := NewBootstrapClient(nil, "", WithSnapshot())
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		lookup   func() (string, error)
+		expected string
+	}{
+		{
+			name:     "Domain",
+			lookup:   firstURL(client.GetDomainRDAPServers, ctx, "example.com"),
+			expected: "https://rdap.verisign.com/com/v1/",
+		},
+		{
+			name:     "IPv4",
+			lookup:   firstURL(client.GetIPAddressRDAPServers, ctx, "8.8.8.8"),
+			expected: "https://rdap.arin.net/registry/",
+		},
+		{
+			name:     "IPv6",
+			lookup:   firstURL(client.GetIPAddressRDAPServers, ctx, "2a00:1450::1"),
+			expected: "https://rdap.db.ripe.net/",
+		},
+		{
+			name:     "ASN",
+			lookup:   firstURL(client.GetAutnumRDAPServers, ctx, "AS23552"),
+			expected: "https://rdap.apnic.net/",
+		},
+		{
+			name:     "Entity",
+			lookup:   firstURL(client.GetEntityRDAPServers, ctx, "GOGL-ARIN"),
+			expected: "https://rdap.arin.net/registry/",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.lookup()
+			if err != nil {
+				t.Fatalf("lookup failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestWithSnapshotFallback(t *testing.T) {
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer mockServer.Close()
+
+	ctx := context.Background()
+
+	client := NewBootstrapClient(mockServer.Client(), mockServer.URL+"/", WithSnapshotFallback())
+	if _, err := client.GetDomainRDAPServers(ctx, "example.com"); err != nil {
+		t.Errorf("expected snapshot fallback, got: %v", err)
+	}
+
+	client = NewBootstrapClient(mockServer.Client(), mockServer.URL+"/")
+	if _, err := client.GetDomainRDAPServers(ctx, "example.com"); err == nil {
+		t.Error("expected an error without snapshot fallback")
+	}
+}
+
+func firstURL(lookup func(context.Context, string) ([]*url.URL, error), ctx context.Context, query string) func() (string, error) {
+	return func() (string, error) {
+		urls, err := lookup(ctx, query)
+		if err != nil {
+			return "", err
+		}
+		return urls[0].String(), nil
+	}
+}
@@ -0,0 +1,310 @@
+package bootstrap
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// The special directory holds the IANA IPv4 and IPv6 Special-Purpose Address
+// Registries and the Special-Use Domain Names registry, in the CSV format
+// published by IANA. Addresses and domains they cover have no RDAP server.
+
+//go:embed special/iana-ipv4-special-registry.csv
+var specialIPv4CSV []byte
+
+//go:embed special/iana-ipv6-special-registry.csv
+var specialIPv6CSV []byte
+
+//go:embed special/special-use-domain.csv
+var specialDomainCSV []byte
+
+// A SpecialPurposeKind classifies special-purpose addresses and domains.
+type SpecialPurposeKind int
+
+const (
+	KindReserved SpecialPurposeKind = iota
+	KindPrivateUse
+	KindSharedAddress
+	KindLoopback
+	KindLinkLocal
+	KindDocumentation
+	KindBenchmarking
+	KindUnspecified
+	KindBroadcast
+	KindTesting
+)
+
+func (k SpecialPurposeKind) String() string {
+	switch k {
+	case KindPrivateUse:
+		return "private-use"
+	case KindSharedAddress:
+		return "shared address space"
+	case KindLoopback:
+		return "loopback"
+	case KindLinkLocal:
+		return "link-local"
+	case KindDocumentation:
+		return "documentation"
+	case KindBenchmarking:
+		return "benchmarking"
+	case KindUnspecified:
+		return "unspecified"
+	case KindBroadcast:
+		return "broadcast"
+	case KindTesting:
+		return "testing"
+	default:
+		return "reserved"
+	}
+}
+
+// SpecialPurpose describes an address block or domain name reserved for a
+// special purpose.
+type SpecialPurpose struct {
+	Kind SpecialPurposeKind
+	// Name is the name of the entry in the IANA registry, e.g. "Private-Use".
+	Name string
+	// Reference is the defining document, e.g. "RFC 1918".
+	Reference string
+	// Prefix is the address block; it is invalid for domain names.
+	Prefix netip.Prefix
+	// Domain is the special-use domain name; it is empty for address blocks.
+	Domain string
+
+	// reachable is set for blocks whose "Globally Reachable" value is not
+	// False. They are kept so that they take precedence over the less
+	// specific blocks containing them, e.g. 2001:3::/32 in 2001::/23.
+	reachable bool
+}
+
+// String returns a short description such as "private-use (RFC 1918)".
+func (s *SpecialPurpose) String() string {
+	if s.Reference == "" {
+		return s.Kind.String()
+	}
+	return fmt.Sprintf("%s (%s)", s.Kind.String(), s.Reference)
+}
+
+// SpecialPurposeError is returned by lookups for special-purpose addresses and
+// domains. It matches both ErrSpecialPurpose and ErrRDAPNotSupported.
+type SpecialPurposeError struct {
+	Query          string
+	SpecialPurpose *SpecialPurpose
+}
+
+func (e *SpecialPurposeError) Error() string {
+	return fmt.Sprintf("%s is %s", e.Query, e.SpecialPurpose.String())
+}
+
+func (e *SpecialPurposeError) Unwrap() []error {
+	return []error{ErrSpecialPurpose, ErrRDAPNotSupported}
+}
+
+// kindByName classifies the entries of the special-purpose address registries.
+var kindByName = map[string]SpecialPurposeKind{
+	"Private-Use":                   KindPrivateUse,
+	"Unique-Local":                  KindPrivateUse,
+	"Shared Address Space":          KindSharedAddress,
+	"Loopback":                      KindLoopback,
+	"Loopback Address":              KindLoopback,
+	"Link Local":                    KindLinkLocal,
+	"Link-Local Unicast":            KindLinkLocal,
+	"Documentation":                 KindDocumentation,
+	"Documentation (TEST-NET-1)":    KindDocumentation,
+	"Documentation (TEST-NET-2)":    KindDocumentation,
+	"Documentation (TEST-NET-3)":    KindDocumentation,
+	"Benchmarking":                  KindBenchmarking,
+	"Unspecified Address":           KindUnspecified,
+	"\"This host on this network\"": KindUnspecified,
+	"Limited Broadcast":             KindBroadcast,
+}
+
+// kindByDomain classifies the entries of the special-use domain registry.
+var kindByDomain = map[string]SpecialPurposeKind{
+	"localhost":            KindLoopback,
+	"example":              KindDocumentation,
+	"example.com":          KindDocumentation,
+	"example.net":          KindDocumentation,
+	"example.org":          KindDocumentation,
+	"test":                 KindTesting,
+	"local":                KindLinkLocal,
+	"254.169.in-addr.arpa": KindLinkLocal,
+	"8.e.f.ip6.arpa":       KindLinkLocal,
+	"9.e.f.ip6.arpa":       KindLinkLocal,
+	"a.e.f.ip6.arpa":       KindLinkLocal,
+	"b.e.f.ip6.arpa":       KindLinkLocal,
+	"10.in-addr.arpa":      KindPrivateUse,
+	"168.192.in-addr.arpa": KindPrivateUse,
+}
+
+// registeredSpecialUseDomains are special-use names that are nevertheless
+// registered in their parent zone and served by its RDAP server.
+var registeredSpecialUseDomains = map[string]bool{
+	"example.com": true,
+	"example.net": true,
+	"example.org": true,
+}
+
+var (
+	specialOnce      sync.Once
+	specialAddresses []*SpecialPurpose
+	specialDomains   map[string]*SpecialPurpose
+	specialErr       error
+)
+
+// LookupSpecialPurposeAddress returns the most specific special-purpose block
+// containing addr, or nil if addr is not special. Blocks which are globally
+// reachable, such as 2002::/16 (6to4) or 192.175.48.0/24 (AS112), are not
+// special: they are delegated like other address space. IPv4-mapped IPv6
+// addresses are looked up in the IPv4 registry.
+func LookupSpecialPurposeAddress(addr netip.Addr) *SpecialPurpose {
+	if loadSpecial() != nil || !addr.IsValid() {
+		return nil
+	}
+	addr = addr.Unmap()
+
+	var match *SpecialPurpose
+	for _, sp := range specialAddresses {
+		if sp.Prefix.Contains(addr) && (match == nil || sp.Prefix.Bits() > match.Prefix.Bits()) {
+			match = sp
+		}
+	}
+	if match != nil && match.reachable {
+		return nil
+	}
+	return match
+}
+
+// LookupSpecialUseDomain returns the special-use domain name domain belongs
+// to, or nil if domain is not special.
+func LookupSpecialUseDomain(domain string) *SpecialPurpose {
+	if loadSpecial() != nil {
+		return nil
+	}
+
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+	labels := strings.Split(domain, ".")
+	for i := range labels {
+		if sp := specialDomains[strings.Join(labels[i:], ".")]; sp != nil {
+			return sp
+		}
+	}
+	return nil
+}
+
+// loadSpecial parses the embedded registries on first use.
+func loadSpecial() error {
+	specialOnce.Do(func() {
+		var v4, v6 []*SpecialPurpose
+		if v4, specialErr = parseSpecialAddresses(specialIPv4CSV); specialErr != nil {
+			return
+		}
+		if v6, specialErr = parseSpecialAddresses(specialIPv6CSV); specialErr != nil {
+			return
+		}
+		specialAddresses = append(v4, v6...)
+		specialDomains, specialErr = parseSpecialDomains(specialDomainCSV)
+	})
+	return specialErr
+}
+
+// footnote matches the footnote markers IANA appends to some fields, e.g. "[2]".
+var footnote = regexp.MustCompile(`\s*\[\d+\]`)
+
+func parseSpecialAddresses(data []byte) ([]*SpecialPurpose, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse special-purpose address registry: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty special-purpose address registry")
+	}
+
+	// the "Globally Reachable" column
+	reachableIndex := -1
+	for i, field := range records[0] {
+		if strings.TrimSpace(field) == "Globally Reachable" {
+			reachableIndex = i
+		}
+	}
+	if reachableIndex < 0 {
+		return nil, errors.New("special-purpose address registry has no Globally Reachable column")
+	}
+
+	var result []*SpecialPurpose
+	for _, record := range records[1:] {
+		if len(record) <= reachableIndex {
+			continue
+		}
+		name := strings.TrimSpace(record[1])
+		// True, N/A and empty values (deprecated blocks) are all reachable
+		reachable := strings.TrimSpace(footnote.ReplaceAllString(record[reachableIndex], "")) != "False"
+		for _, block := range strings.Split(footnote.ReplaceAllString(record[0], ""), ",") {
+			prefix, err := netip.ParsePrefix(strings.TrimSpace(block))
+			if err != nil {
+				return nil, fmt.Errorf("invalid special-purpose block %q: %w", block, err)
+			}
+			result = append(result, &SpecialPurpose{
+				Kind:      kindByName[name],
+				Name:      name,
+				Reference: formatReference(record[2]),
+				Prefix:    prefix,
+				reachable: reachable,
+			})
+		}
+	}
+	return result, nil
+}
+
+func parseSpecialDomains(data []byte) (map[string]*SpecialPurpose, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse special-use domain registry: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errors.New("empty special-use domain registry")
+	}
+
+	result := make(map[string]*SpecialPurpose)
+	for _, record := range records[1:] {
+		if len(record) < 2 {
+			continue
+		}
+		domain := strings.ToLower(strings.TrimSuffix(strings.TrimSpace(record[0]), "."))
+		if registeredSpecialUseDomains[domain] {
+			continue
+		}
+
+		kind, ok := kindByDomain[domain]
+		if !ok && strings.HasSuffix(domain, ".172.in-addr.arpa") {
+			kind = KindPrivateUse
+		}
+		result[domain] = &SpecialPurpose{
+			Kind:      kind,
+			Name:      "Special-Use Domain Name",
+			Reference: formatReference(record[1]),
+			Domain:    domain,
+		}
+	}
+	return result, nil
+}
+
+// rfcReference matches the first RFC cited in a registry reference field.
+var rfcReference = regexp.MustCompile(`\[RFC(\d+)\]`)
+
+// formatReference turns a reference field such as "[RFC1918]" or
+// "[RFC1122], Section 3.2.1.3" into "RFC 1918" and "RFC 1122".
+func formatReference(ref string) string {
+	if m := rfcReference.FindStringSubmatch(ref); m != nil {
+		return "RFC " + m[1]
+	}
+	return strings.Trim(strings.TrimSpace(ref), "[]")
+}
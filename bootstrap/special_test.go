@@ -0,0 +1,127 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestLookupSpecialPurposeAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "Private-use",
+			input:    "10.1.2.3",
+			expected: "private-use (RFC 1918)",
+		},
+		{
+			name:     "Documentation",
+			input:    "192.0.2.1",
+			expected: "documentation (RFC 5737)",
+		},
+		{
+			name:     "Most specific block",
+			input:    "0.0.0.0",
+			expected: "unspecified (RFC 1122)",
+		},
+		{
+			name:     "IPv6 link-local",
+			input:    "fe80::1",
+			expected: "link-local (RFC 4291)",
+		},
+		{
+			name:     "IPv4-mapped IPv6",
+			input:    "::ffff:192.168.1.1",
+			expected: "private-use (RFC 1918)",
+		},
+		{
+			name:     "Global unicast",
+			input:    "8.8.8.8",
+			expected: "",
+		},
+		{
+			name:     "Globally reachable 6to4",
+			input:    "2002:c000:204::1",
+			expected: "",
+		},
+		{
+			name:     "Globally reachable block in IETF protocol assignments",
+			input:    "2001:3::1",
+			expected: "",
+		},
+		{
+			name:     "Benchmarking in IETF protocol assignments",
+			input:    "2001:2::1",
+			expected: "benchmarking (RFC 5180)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LookupSpecialPurposeAddress(netip.MustParseAddr(tt.input))
+			if tt.expected == "" {
+				if result != nil {
+					t.Errorf("Expected no special purpose, got %s", result)
+				}
+				return
+			}
+			if result == nil || result.String() != tt.expected {
+				t.Errorf("Expected %s, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestLookupSpecialUseDomain(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected SpecialPurposeKind
+		special  bool
+	}{
+		{name: "local", input: "printer.local", expected: KindLinkLocal, special: true},
+		{name: "onion", input: "example.onion.", expected: KindReserved, special: true},
+		{name: "test", input: "www.TEST", expected: KindTesting, special: true},
+		{name: "Private reverse zone", input: "4.3.20.172.in-addr.arpa", expected: KindPrivateUse, special: true},
+		{name: "Registered example domain", input: "example.com", special: false},
+		{name: "Regular domain", input: "perihwk.com", special: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LookupSpecialUseDomain(tt.input)
+			if (result != nil) != tt.special {
+				t.Fatalf("Expected special: %v, got %v", tt.special, result)
+			}
+			if result != nil && result.Kind != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, result.Kind)
+			}
+		})
+	}
+}
+
+func TestLookupSpecialPurposeWithoutNetwork(t *testing.T) {
+	// nil http client: any attempt to fetch a registry would panic
+	client := NewBootstrapClient(nil, "")
+	ctx := context.Background()
+
+	var spErr *SpecialPurposeError
+	if _, err := client.GetIPAddressRDAPServers(ctx, "10.1.2.3"); !errors.As(err, &spErr) || !errors.Is(err, ErrRDAPNotSupported) {
+		t.Errorf("expected SpecialPurposeError, got %v", err)
+	}
+	if _, err := client.GetDomainRDAPServers(ctx, "host.local"); !errors.Is(err, ErrSpecialPurpose) {
+		t.Errorf("expected ErrSpecialPurpose, got %v", err)
+	}
+
+	// an override for special-purpose space takes precedence
+	if err := client.AddOverride(IPv4, "10.0.0.0/8", "https://ipam.corp.internal/rdap/"); err != nil {
+		t.Fatalf("failed to add override: %v", err)
+	}
+	if _, err := client.GetIPAddressRDAPServers(ctx, "10.1.2.3"); err != nil {
+		t.Errorf("expected override to be used, got %v", err)
+	}
+}
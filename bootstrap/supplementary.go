@@ -0,0 +1,50 @@
+package bootstrap
+
+import (
+	_ "embed"
+)
+
+// The supplementary registry lists ccTLDs which operate a public RDAP server
+// but are not (yet) in the IANA DNS registry. It uses the IANA bootstrap file
+// format, and its version and publication members identify the list.
+//
+//go:embed supplementary/dns.json
+var defaultSupplementary []byte
+
+// DefaultSupplementary returns the supplementary DNS registry shipped with
+// this package.
+func DefaultSupplementary() (*Registry, error) {
+	return parseRegistry(DNS, defaultSupplementary)
+}
+
+// WithSupplementary consults registry for domains whose TLD is not in the IANA
+// DNS registry. If registry is nil the list returned by DefaultSupplementary
+// is used.
+func WithSupplementary(registry *Registry) Option {
+	return func(c *Client) {
+		if registry == nil {
+			var err error
+			if registry, err = DefaultSupplementary(); err != nil {
+				// the embedded file is checked by the tests, this cannot happen
+				panic(err)
+			}
+		}
+		c.supplementary = registry
+	}
+}
+
+// SetSupplementary replaces the supplementary DNS registry, e.g. with one
+// read by LoadRegistryFile. A nil registry disables the supplementary lookup.
+func (c *Client) SetSupplementary(registry *Registry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.supplementary = registry
+}
+
+// Supplementary returns the supplementary DNS registry, or nil if none is
+// configured.
+func (c *Client) Supplementary() *Registry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.supplementary
+}
@@ -0,0 +1,96 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLookupDomainSupplementary(t *testing.T) {
+	supplementary, err := DefaultSupplementary()
+	if err != nil {
+		t.Fatalf("failed to load default supplementary registry: %v", err)
+	}
+	if supplementary.Version == "" || supplementary.Publication == "" {
+		t.Error("default supplementary registry is not versioned")
+	}
+
+	client := NewBootstrapClient(nil, "", WithSnapshot(), WithSupplementary(nil))
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		domain   string
+		expected Source
+	}{
+		{
+			name:     "IANA",
+			domain:   "example.com",
+			expected: SourceIANA,
+		},
+		{
+			name:     "Supplementary",
+			domain:   "example.de",
+			expected: SourceSupplementary,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := client.LookupDomain(ctx, tt.domain)
+			if err != nil {
+				t.Fatalf("lookup failed: %v", err)
+			}
+			if result.Source != tt.expected {
+				t.Errorf("Expected source %s, got %s", tt.expected, result.Source)
+			}
+		})
+	}
+
+	client.SetSupplementary(nil)
+	if _, err := client.LookupDomain(ctx, "example.de"); !errors.Is(err, ErrRDAPNotSupported) {
+		t.Errorf("expected ErrRDAPNotSupported without supplementary registry, got %v", err)
+	}
+}
+
+func TestSupplementaryFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "supplementary.json")
+	if err := os.WriteFile(path, []byte(testDNSRegistry), 0o644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	supplementary, err := LoadRegistryFile(path)
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+
+	client := NewBootstrapClient(nil, "", WithSnapshot(), WithSupplementary(supplementary))
+	result, err := client.LookupDomain(context.Background(), "example.org")
+	if err != nil {
+		t.Fatalf("lookup failed: %v", err)
+	}
+	// .org is in the IANA registry, which takes precedence
+	if result.Source != SourceIANA {
+		t.Errorf("Expected source %s, got %s", SourceIANA, result.Source)
+	}
+}
+
+func TestLookupDomainSupplementarySpecialUse(t *testing.T) {
+	supplementary, err := LoadRegistry(strings.NewReader(`{
+  "version": "1.0",
+  "services": [[["local"], ["https://rdap.example.net/"]]]
+}`))
+	if err != nil {
+		t.Fatalf("failed to load registry: %v", err)
+	}
+
+	// nil http client: special-use names are answered without the registry
+	client := NewBootstrapClient(nil, "", WithSupplementary(supplementary))
+	var spErr *SpecialPurposeError
+	if _, err := client.LookupDomain(context.Background(), "host.local"); !errors.As(err, &spErr) {
+		t.Errorf("expected SpecialPurposeError, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package bootstrap
 
 import (
 	"fmt"
+	"net/netip"
 	"net/url"
 	"strconv"
 	"strings"
@@ -40,3 +41,12 @@ func parseASN(asn string) (uint64, error) {
 
 	return uint64(result), nil
 }
+
+// unmapPrefix converts an IPv4-mapped IPv6 prefix (e.g. ::ffff:10.0.0.0/104)
+// into the equivalent IPv4 prefix. Other prefixes are returned unchanged.
+func unmapPrefix(prefix netip.Prefix) netip.Prefix {
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		return netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return prefix
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/netip"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/perihwk/openrdap/bootstrap"
@@ -43,63 +45,69 @@ func (r RegistrySearchType) Path() string {
 type Client struct {
 	httpClient      *http.Client
 	bootstrapClient *bootstrap.Client
+
+	// hydrateConcurrency is the number of concurrent requests used to
+	// hydrate responses, 0 if hydration is disabled.
+	hydrateConcurrency int
 }
 
+// An Option configures a Client.
+type Option func(*Client)
+
 func NewClient(
 	httpClient *http.Client,
 	bootstrapClient *bootstrap.Client,
+	opts ...Option,
 ) *Client {
 
 	if bootstrapClient == nil {
 		bootstrapClient = bootstrap.NewBootstrapClient(httpClient, "")
 	}
 
-	return &Client{
+	c := &Client{
 		httpClient:      httpClient,
 		bootstrapClient: bootstrapClient,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
+// HTTPClient returns the HTTP client used for requests, http.DefaultClient if
+// none was given to NewClient.
+func (c *Client) HTTPClient() *http.Client {
+	if c.httpClient == nil {
+		return http.DefaultClient
+	}
+	return c.httpClient
+}
+
+// GetRDAPInfoFromServer queries rdapServer directly, bypassing the bootstrap
+// registries. The response is decoded with Decode, so the result is the
+// object class the server answered with, which need not match searchType.
+// Error responses are returned as *ErrorResponse.
 func (c *Client) GetRDAPInfoFromServer(ctx context.Context, rdapServer, query string, searchType RegistrySearchType) (any, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", rdapServer+fmt.Sprintf(searchType.Path(), query), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := c.httpClient.Do(req)
+	body, err := c.do(req)
 	if err != nil {
-		fmt.Println(err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != 200 {
-		fmt.Printf("server %s returned non-200 status code: %s", rdapServer, resp.Status)
+		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	result, err := Decode(body)
 	if err != nil {
-		fmt.Printf("err: %v\n", err)
-	}
-
-	var result interface{}
-	switch searchType {
-	case DNS:
-		result = &Domain{}
-	case IPv4, IPv6:
-		result = &IPNetwork{}
-	case ASN:
-		result = &Autnum{}
-	default:
-		return nil, fmt.Errorf("unsupported search type")
-	}
-
-	if err = json.Unmarshal(body, result); err != nil {
-		return nil, fmt.Errorf("error parsing RDAP response: %w", err)
+		return nil, err
 	}
-
+	c.autoHydrate(ctx, result)
 	return result, nil
 }
 
+// GetRDAPFromDomain queries the RDAP server responsible for domain. Special-use
+// domain names are answered locally with a *bootstrap.SpecialPurposeError.
 func (c *Client) GetRDAPFromDomain(ctx context.Context, domain string) (*Domain, error) {
 	registryServers, err := c.bootstrapClient.GetDomainRDAPServers(ctx, domain)
 	if err != nil {
@@ -107,81 +115,61 @@ func (c *Client) GetRDAPFromDomain(ctx context.Context, domain string) (*Domain,
 	}
 
 	var domainResp *Domain
-	for i, u := range registryServers {
-		localSrv := u.String()
-		// use first https RDAP server. If no https server then use whatever the last option was
-		if u.Scheme == "https" || i == len(registryServers)-1 {
-			if localSrv, err = url.JoinPath(localSrv, "domain", domain); err != nil {
-				return nil, err
-			}
-
-			req, err := http.NewRequestWithContext(ctx, "GET", localSrv, nil)
-			if err != nil {
-				return nil, err
-			}
-
-			resp, err := c.httpClient.Do(req)
-			if err != nil {
-				return nil, err
-			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != 200 {
-				return nil, fmt.Errorf("server %s returned non-200 status code: %s", localSrv, resp.Status)
-			}
-
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return nil, fmt.Errorf("error reading RDAP response: %w", err)
-			}
-
-			if err = json.Unmarshal(body, &domainResp); err != nil {
-				return nil, fmt.Errorf("error parsing RDAP response: %w", err)
-			}
-		}
+	if err = c.queryServers(ctx, registryServers, &domainResp, "domain", domain); err != nil {
+		return nil, err
 	}
+	c.autoHydrate(ctx, domainResp)
 	return domainResp, nil
 }
 
+// GetRDAPFromIP queries the RDAP server responsible for ip. Special-purpose
+// addresses are answered locally with a *bootstrap.SpecialPurposeError.
 func (c *Client) GetRDAPFromIP(ctx context.Context, ip string) (*IPNetwork, error) {
-	registryServers, err := c.bootstrapClient.GetIPAddressRDAPServers(ctx, ip)
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return nil, fmt.Errorf("input %s is not an IP Address", ip)
+	}
+	return c.GetRDAPFromIPAddr(ctx, addr)
+}
+
+// GetRDAPFromIPAddr is like GetRDAPFromIP for a netip.Addr. IPv4-mapped IPv6
+// addresses are queried as IPv4 addresses.
+func (c *Client) GetRDAPFromIPAddr(ctx context.Context, addr netip.Addr) (*IPNetwork, error) {
+	registryServers, err := c.bootstrapClient.GetIPAddrRDAPServers(ctx, addr)
 	if err != nil {
 		return nil, err
 	}
 
 	var ipAddressResp *IPNetwork
-	for i, u := range registryServers {
-		// use first https RDAP server. If no https server then use whatever the last option was
-		if u.Scheme == "https" || i == len(registryServers)-1 {
-			if u.Path, err = url.JoinPath(u.Path, "ip", ip); err != nil {
-				return nil, err
-			}
-			req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
-			if err != nil {
-				return nil, err
-			}
-
-			resp, err := c.httpClient.Do(req)
-			if err != nil {
-				return nil, err
-			}
-
-			if resp.StatusCode != 200 {
-				return nil, fmt.Errorf("server %s returned non-200 status code: %s", u.Path, resp.Status)
-			}
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return nil, fmt.Errorf("error reading RDAP response: %w", err)
-			}
-
-			if err = json.Unmarshal(body, &ipAddressResp); err != nil {
-				return nil, fmt.Errorf("error parsing RDAP response: %w", err)
-			}
-		}
+	if err = c.queryServers(ctx, registryServers, &ipAddressResp, "ip", addr.Unmap().WithZone("").String()); err != nil {
+		return nil, err
 	}
+	c.autoHydrate(ctx, ipAddressResp)
 	return ipAddressResp, nil
 }
 
+// GetRDAPFromIPPrefix queries the RDAP server responsible for a network, e.g.
+// ip/192.0.2.0/24. The server returns the most specific network covering the
+// whole of prefix. IPv4-mapped IPv6 prefixes, e.g. ::ffff:192.0.2.0/120, are
+// queried as IPv4 prefixes.
+func (c *Client) GetRDAPFromIPPrefix(ctx context.Context, prefix netip.Prefix) (*IPNetwork, error) {
+	registryServers, err := c.bootstrapClient.GetIPPrefixRDAPServers(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix = prefix.Masked()
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	var ipNetworkResp *IPNetwork
+	if err = c.queryServers(ctx, registryServers, &ipNetworkResp, "ip", prefix.Addr().String(), strconv.Itoa(prefix.Bits())); err != nil {
+		return nil, err
+	}
+	c.autoHydrate(ctx, ipNetworkResp)
+	return ipNetworkResp, nil
+}
+
 func (c *Client) GetRDAPFromAutnum(ctx context.Context, asn string) (*Autnum, error) {
 	registryServers, err := c.bootstrapClient.GetAutnumRDAPServers(ctx, asn)
 	if err != nil {
@@ -190,35 +178,69 @@ func (c *Client) GetRDAPFromAutnum(ctx context.Context, asn string) (*Autnum, er
 
 	asn = strings.TrimPrefix(strings.ToUpper(asn), "AS")
 	var autnumResp *Autnum
-	for i, u := range registryServers {
-		// use first https RDAP server. If no https server then use whatever the last option was
-		if u.Scheme == "https" || i == len(registryServers)-1 {
-			if u.Path, err = url.JoinPath(u.Path, "autnum", asn); err != nil {
-				return nil, err
-			}
-
-			req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
-			if err != nil {
-				return nil, err
-			}
-
-			resp, err := c.httpClient.Do(req)
-			if err != nil {
-				return nil, err
-			}
-
-			if resp.StatusCode != 200 {
-				return nil, fmt.Errorf("server %s returned non-200 status code: %s", u.Path, resp.Status)
-			}
-			body, err := io.ReadAll(resp.Body)
-			if err != nil {
-				return nil, fmt.Errorf("error reading RDAP response: %w", err)
-			}
-
-			if err = json.Unmarshal(body, &autnumResp); err != nil {
-				return nil, fmt.Errorf("error parsing RDAP response: %w", err)
-			}
-		}
+	if err = c.queryServers(ctx, registryServers, &autnumResp, "autnum", asn); err != nil {
+		return nil, err
 	}
+	c.autoHydrate(ctx, autnumResp)
 	return autnumResp, nil
 }
+
+// queryServers requests path from the first https server of registryServers,
+// or the last server if none of them uses https, and parses the response into
+// result.
+func (c *Client) queryServers(ctx context.Context, registryServers []*url.URL, result any, path ...string) error {
+	if len(registryServers) == 0 {
+		return bootstrap.ErrRDAPNotSupported
+	}
+
+	server := registryServers[len(registryServers)-1]
+	for _, u := range registryServers {
+		if u.Scheme == "https" {
+			server = u
+			break
+		}
+	}
+
+	// JoinPath copies the URL, the bootstrap registry must not be modified
+	u := server.JoinPath(path...)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("error parsing RDAP response: %w", err)
+	}
+	return nil
+}
+
+// do sends req and returns the response body. Responses other than 200 OK
+// are returned as error, as *ErrorResponse if the server sent one.
+func (c *Client) do(req *http.Request) ([]byte, error) {
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+
+	resp, err := c.HTTPClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading RDAP response: %w", err)
+	}
+
+	if resp.StatusCode != 200 {
+		// prefer the error response of the server if it sent one
+		if _, err := Decode(body); isErrorResponse(err) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("server %s returned non-200 status code: %s", req.URL.String(), resp.Status)
+	}
+	return body, nil
+}
@@ -4,8 +4,12 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/netip"
 	"os"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/perihwk/openrdap/bootstrap"
 )
 
 func TestGetRDAPInfoFromServer(t *testing.T) {
@@ -155,3 +159,53 @@ func TestGetRDAPFromAutnum(t *testing.T) {
 		t.Errorf("Expected domain name %s, got %s", expectedName, asnInfo.Name)
 	}
 }
+
+func TestGetRDAPFromIPPrefix(t *testing.T) {
+	fileData, err := os.ReadFile("test/example_ip_8888.json")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var requestPaths []string
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPaths = append(requestPaths, r.URL.Path)
+		w.Header().Set("Content-Type", "application/rdap+json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(fileData)
+	}))
+	defer mockServer.Close()
+
+	bootstrapClient := bootstrap.NewBootstrapClient(mockServer.Client(), "")
+	if err := bootstrapClient.AddOverride(bootstrap.IPv4, "8.0.0.0/8", mockServer.URL+"/rdap/"); err != nil {
+		t.Fatalf("failed to add override: %v", err)
+	}
+	client := NewClient(mockServer.Client(), bootstrapClient)
+
+	ctx := context.Background()
+	if _, err := client.GetRDAPFromIPPrefix(ctx, netip.MustParsePrefix("8.8.8.1/24")); err != nil {
+		t.Fatalf("Failed to get RDAP info: %v", err)
+	}
+	ipInfo, err := client.GetRDAPFromIPAddr(ctx, netip.MustParseAddr("8.8.8.8"))
+	if err != nil {
+		t.Fatalf("Failed to get RDAP info: %v", err)
+	}
+
+	expectedName := "GOGL"
+	if ipInfo.Name != expectedName {
+		t.Errorf("Expected network name %s, got %s", expectedName, ipInfo.Name)
+	}
+
+	// IPv4-mapped IPv6 addresses and prefixes are queried in IPv4 form
+	if _, err := client.GetRDAPFromIPAddr(ctx, netip.MustParseAddr("::ffff:8.8.8.8")); err != nil {
+		t.Fatalf("Failed to get RDAP info: %v", err)
+	}
+	if _, err := client.GetRDAPFromIPPrefix(ctx, netip.MustParsePrefix("::ffff:8.8.8.0/120")); err != nil {
+		t.Fatalf("Failed to get RDAP info: %v", err)
+	}
+
+	// the second query must not include the path of the first one
+	expectedPaths := []string{"/rdap/ip/8.8.8.0/24", "/rdap/ip/8.8.8.8", "/rdap/ip/8.8.8.8", "/rdap/ip/8.8.8.0/24"}
+	if !cmp.Equal(requestPaths, expectedPaths) {
+		t.Errorf("unexpected request paths %s", cmp.Diff(expectedPaths, requestPaths))
+	}
+}
@@ -1,5 +1,11 @@
 package openrdap
 
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
 // RDAP Conformance
 // Appears in topmost JSON objects only, embedded (no separate type):
 // Conformance []string `rdap:"rdapConformance"`
@@ -10,33 +16,33 @@ package openrdap
 //
 // https://tools.ietf.org/html/rfc7483#section-4.2
 type Link struct {
-	Value    string   `json:"value"`
-	Rel      string   `json:"rel"`
-	Href     string   `json:"href"`
-	HrefLang []string `json:"hreflang"`
-	Title    string   `json:"title"`
-	Media    string   `json:"media"`
-	Type     string   `json:"type"`
+	Value    string   `json:"value,omitempty"`
+	Rel      string   `json:"rel,omitempty"`
+	Href     string   `json:"href,omitempty"`
+	HrefLang []string `json:"hreflang,omitempty"`
+	Title    string   `json:"title,omitempty"`
+	Media    string   `json:"media,omitempty"`
+	Type     string   `json:"type,omitempty"`
 }
 
 // Notice contains information about the entire RDAP response.
 //
 // https://tools.ietf.org/html/rfc7483#section-4.3
 type Notice struct {
-	Title       string   `json:"title"`
-	Type        string   `json:"type"`
-	Description []string `json:"description"`
-	Links       []Link   `json:"links"`
+	Title       string   `json:"title,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Description []string `json:"description,omitempty"`
+	Links       []Link   `json:"links,omitempty"`
 }
 
 // Remark contains information about the containing RDAP object.
 //
 // https://tools.ietf.org/html/rfc7483#section-4.3
 type Remark struct {
-	Title       string   `json:"title"`
-	Type        string   `json:"type"`
-	Description []string `json:"description"`
-	Links       []Link   `json:"links"`
+	Title       string   `json:"title,omitempty"`
+	Type        string   `json:"type,omitempty"`
+	Description []string `json:"description,omitempty"`
+	Links       []Link   `json:"links,omitempty"`
 }
 
 // Language Identifier
@@ -49,10 +55,43 @@ type Remark struct {
 //
 // https://tools.ietf.org/html/rfc7483#section-4.5
 type Event struct {
-	Action string `json:"eventAction"`
-	Actor  string `json:"eventActor"`
-	Date   string `json:"eventDate"`
-	Links  []Link `json:"links"`
+	Action string `json:"eventAction,omitempty"`
+	Actor  string `json:"eventActor,omitempty"`
+	Date   string `json:"eventDate,omitempty"`
+	Links  []Link `json:"links,omitempty"`
+
+	// Time is Date parsed as an RFC 3339 timestamp. It is the zero time if
+	// Date is empty or cannot be parsed.
+	Time time.Time `json:"-"`
+}
+
+// Event actions registered by RFC 8056 and later RFCs.
+//
+// https://www.iana.org/assignments/rdap-json-values/rdap-json-values.xhtml
+const (
+	EventRegistration             = "registration"
+	EventReregistration           = "reregistration"
+	EventLastChanged              = "last changed"
+	EventExpiration               = "expiration"
+	EventDeletion                 = "deletion"
+	EventReinstantiation          = "reinstantiation"
+	EventTransfer                 = "transfer"
+	EventLocked                   = "locked"
+	EventUnlocked                 = "unlocked"
+	EventLastUpdateOfRDAPDatabase = "last update of RDAP database"
+	EventRegistrarExpiration      = "registrar expiration"
+	EventEnumValidationExpiration = "enum validation expiration"
+)
+
+// UnmarshalJSON for Event to parse the event date into Time
+func (e *Event) UnmarshalJSON(data []byte) error {
+	type Alias Event
+	if err := json.Unmarshal(data, (*Alias)(e)); err != nil {
+		return err
+	}
+
+	e.Time, _ = parseEventDate(e.Date)
+	return nil
 }
 
 // Port43 indicates the IP/FQDN of a WHOIS server.
@@ -65,8 +104,8 @@ type Event struct {
 //
 // https://tools.ietf.org/html/rfc7483#section-4.8
 type PublicID struct {
-	Type       string `json:"type"`
-	Identifier string `json:"identifier"`
+	Type       string `json:"type,omitempty"`
+	Identifier string `json:"identifier,omitempty"`
 }
 
 // ObjectClassName specifies the object type as a string.
@@ -77,5 +116,43 @@ type PublicID struct {
 
 // Common contains fields which may appear anywhere in an RDAP response.
 type Common struct {
-	Lang string `json:"lang"`
+	Lang string `json:"lang,omitempty"`
+}
+
+// eventDateLayouts are tried in order when parsing event dates. RFC 9083
+// requires RFC 3339, the others are variants seen in registry responses.
+var eventDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// parseEventDate parses an event date, reporting whether it could be parsed.
+// Dates without a time zone are taken to be UTC.
+func parseEventDate(date string) (time.Time, bool) {
+	date = strings.TrimSpace(date)
+	if date == "" {
+		return time.Time{}, false
+	}
+	// RFC 3339 allows a lower case "t" and "z"
+	date = strings.ToUpper(date)
+
+	for _, layout := range eventDateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// eventByAction returns the first event with the given action, or nil.
+func eventByAction(events []Event, action string) *Event {
+	for i := range events {
+		if strings.EqualFold(events[i].Action, action) {
+			return &events[i]
+		}
+	}
+	return nil
 }
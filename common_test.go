@@ -0,0 +1,113 @@
+package openrdap
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseEventDate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected time.Time
+		ok       bool
+	}{
+		{
+			name:     "RFC 3339 UTC",
+			input:    "2019-02-05T03:32:53Z",
+			expected: time.Date(2019, 2, 5, 3, 32, 53, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			name:     "RFC 3339 offset",
+			input:    "2023-12-28T17:24:56-05:00",
+			expected: time.Date(2023, 12, 28, 22, 24, 56, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			name:     "Fractional seconds, lower case",
+			input:    "2024-01-05t15:38:38.123z",
+			expected: time.Date(2024, 1, 5, 15, 38, 38, 123000000, time.UTC),
+			ok:       true,
+		},
+		{
+			name:     "No time zone",
+			input:    "2024-01-05T15:38:38",
+			expected: time.Date(2024, 1, 5, 15, 38, 38, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			name:     "Date only",
+			input:    "2024-01-05",
+			expected: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+			ok:       true,
+		},
+		{
+			name:  "Invalid",
+			input: "05/01/2024",
+			ok:    false,
+		},
+		{
+			name:  "Empty",
+			input: "",
+			ok:    false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, ok := parseEventDate(tt.input)
+			if ok != tt.ok {
+				t.Fatalf("Expected ok: %v, got: %v", tt.ok, ok)
+			}
+			if !result.Equal(tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestDomainLifecycle(t *testing.T) {
+	fileData, err := os.ReadFile("test/example_domain_perihwk.json")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var domain Domain
+	if err := json.Unmarshal(fileData, &domain); err != nil {
+		t.Fatalf("failed to parse domain: %v", err)
+	}
+
+	registered, ok := RegistrationDate(&domain)
+	if !ok || !registered.Equal(time.Date(2019, 2, 5, 3, 32, 53, 0, time.UTC)) {
+		t.Errorf("unexpected registration date %v", registered)
+	}
+	expires, ok := ExpirationDate(&domain)
+	if !ok || !expires.Equal(time.Date(2025, 2, 5, 3, 32, 53, 0, time.UTC)) {
+		t.Errorf("unexpected expiration date %v", expires)
+	}
+	if event := domain.GetEventByName(EventLastChanged); event == nil || event.Date != "2024-01-05T15:38:38Z" {
+		t.Errorf("expected the original date string to be kept, got %+v", event)
+	}
+
+	now := time.Date(2025, 3, 7, 3, 32, 53, 0, time.UTC)
+	if age, ok := Age(&domain, now); !ok || age != (6*365+2+30)*24*time.Hour {
+		t.Errorf("unexpected age %v", age)
+	}
+	if untilExpiry, ok := TimeUntilExpiry(&domain, now); !ok || untilExpiry != -30*24*time.Hour {
+		t.Errorf("expected the domain to have expired 30 days ago, got %v", untilExpiry)
+	}
+	if changed, ok := LastChangedDate(&domain); !ok || !changed.Equal(time.Date(2024, 1, 5, 15, 38, 38, 0, time.UTC)) {
+		t.Errorf("unexpected last changed date %v", changed)
+	}
+
+	var network IPNetwork
+	if _, ok := ExpirationDate(&network); ok {
+		t.Error("expected no expiration date without events")
+	}
+	if _, ok := Age(&network, now); ok {
+		t.Error("expected no age without events")
+	}
+}
@@ -0,0 +1,476 @@
+// Package conformance tests an RDAP server, given its base URL, against the
+// HTTP requirements of RFC 7480 and RFC 7481: media type, CORS, status codes
+// for unknown objects and malformed queries, help, HEAD, redirects and https.
+//
+// Objects returned by the server are also checked with the validator
+// package.
+package conformance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/perihwk/openrdap"
+	"github.com/perihwk/openrdap/validator"
+)
+
+// Status is the outcome of a check.
+type Status int
+
+const (
+	// StatusPass means the server behaved as required.
+	StatusPass Status = iota
+	// StatusSkip means the check could not be run with the given Config.
+	StatusSkip
+	// StatusWarn means the server violated a SHOULD of the specification.
+	StatusWarn
+	// StatusFail means the server violated a MUST of the specification.
+	StatusFail
+)
+
+func (s Status) String() string {
+	switch s {
+	case StatusPass:
+		return "pass"
+	case StatusSkip:
+		return "skip"
+	case StatusWarn:
+		return "warn"
+	case StatusFail:
+		return "fail"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// Check names reported in results.
+const (
+	CheckHTTPS       = "https"
+	CheckLookup      = "lookup"
+	CheckContentType = "content-type"
+	CheckCORS        = "cors"
+	CheckHEAD        = "head"
+	CheckNotFound    = "not-found"
+	CheckBadRequest  = "bad-request"
+	CheckHelp        = "help"
+	CheckRedirect    = "redirect"
+)
+
+// rdapMediaType is the media type of RDAP responses.
+//
+// https://datatracker.ietf.org/doc/html/rfc7480#section-4.2
+const rdapMediaType = "application/rdap+json"
+
+// A Query is a lookup of a single object, e.g. {openrdap.DNS, "example.com"}.
+type Query struct {
+	Type  openrdap.RegistrySearchType
+	Value string
+}
+
+func (q Query) path() string {
+	return fmt.Sprintf(q.Type.Path(), q.Value)
+}
+
+// Config describes the server under test.
+type Config struct {
+	// BaseURL of the server, e.g. "https://rdap.example.com/".
+	BaseURL string
+
+	// Queries are objects the server holds. Lookup, media type, CORS and
+	// HEAD are checked for each of them.
+	Queries []Query
+
+	// NotFound are queries for objects the server does not hold, which must
+	// be answered with 404. If empty, queries are derived from the domain
+	// and entity Queries.
+	NotFound []Query
+
+	// Malformed are queries the server must reject with 400. If empty, a
+	// malformed query is derived for each query type in Queries.
+	Malformed []Query
+
+	// Redirect is a query the server does not answer itself but redirects
+	// to another server, e.g. for a domain of another TLD. The redirect
+	// check is skipped if it is nil.
+	Redirect *Query
+}
+
+// A Result is the outcome of a check for a single target.
+type Result struct {
+	Check string
+	// Target is the path or URL the check was run against.
+	Target  string
+	Status  Status
+	Message string
+}
+
+func (r Result) String() string {
+	s := fmt.Sprintf("%s %s %s", r.Status, r.Check, r.Target)
+	if r.Message != "" {
+		s += ": " + r.Message
+	}
+	return s
+}
+
+// Report holds the results of a run in the order the checks were run.
+type Report struct {
+	BaseURL string
+	Results []Result
+}
+
+// Failed reports whether any check failed.
+func (r *Report) Failed() bool {
+	return len(r.Failures()) > 0
+}
+
+// Failures returns the results with StatusFail.
+func (r *Report) Failures() []Result {
+	var failures []Result
+	for _, result := range r.Results {
+		if result.Status == StatusFail {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// WriteTo writes the results one per line, followed by a summary.
+func (r *Report) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	counts := make(map[Status]int)
+	for _, result := range r.Results {
+		fmt.Fprintln(&b, result)
+		counts[result.Status]++
+	}
+	fmt.Fprintf(&b, "%s: %d passed, %d warnings, %d failed, %d skipped\n", r.BaseURL,
+		counts[StatusPass], counts[StatusWarn], counts[StatusFail], counts[StatusSkip])
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// Runner runs the conformance checks against a server.
+type Runner struct {
+	client *openrdap.Client
+	config Config
+}
+
+// NewRunner returns a Runner which sends its requests with client.
+func NewRunner(client *openrdap.Client, config Config) *Runner {
+	if !strings.HasSuffix(config.BaseURL, "/") {
+		config.BaseURL += "/"
+	}
+	return &Runner{client: client, config: config}
+}
+
+// Run runs all checks and returns the report. Failing checks are reported in
+// the report; an error is only returned for an unusable Config or when ctx is
+// done.
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	base, err := url.Parse(r.config.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if !base.IsAbs() {
+		return nil, fmt.Errorf("base URL %q is not absolute", r.config.BaseURL)
+	}
+
+	report := &Report{BaseURL: r.config.BaseURL}
+	add := func(result Result) {
+		report.Results = append(report.Results, result)
+	}
+
+	add(r.checkHTTPS(base))
+	for _, q := range r.config.Queries {
+		add(r.checkLookup(ctx, q))
+		for _, result := range r.checkResponse(ctx, q.path()) {
+			add(result)
+		}
+		add(r.checkHEAD(ctx, q.path()))
+	}
+	for _, q := range r.notFoundQueries() {
+		add(r.checkStatus(ctx, CheckNotFound, q.path(), http.StatusNotFound))
+	}
+	for _, q := range r.malformedQueries() {
+		add(r.checkStatus(ctx, CheckBadRequest, q.path(), http.StatusBadRequest))
+	}
+	add(r.checkHelp(ctx))
+	add(r.checkRedirect(ctx))
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// checkHTTPS checks the server is reached over https.
+//
+// https://datatracker.ietf.org/doc/html/rfc7481#section-3.2
+func (r *Runner) checkHTTPS(base *url.URL) Result {
+	result := Result{Check: CheckHTTPS, Target: r.config.BaseURL}
+	if base.Scheme != "https" {
+		result.Status = StatusFail
+		result.Message = fmt.Sprintf("scheme is %q", base.Scheme)
+	}
+	return result
+}
+
+// checkLookup queries q with the Client and validates the decoded object.
+func (r *Runner) checkLookup(ctx context.Context, q Query) Result {
+	result := Result{Check: CheckLookup, Target: q.path()}
+
+	resp, err := r.client.GetRDAPInfoFromServer(ctx, r.config.BaseURL, q.Value, q.Type)
+	if err != nil {
+		return fail(result, "%v", err)
+	}
+	obj, ok := resp.(openrdap.Object)
+	if !ok {
+		return fail(result, "unexpected response %T", resp)
+	}
+
+	var findings []string
+	severity := validator.SeverityInfo
+	for _, f := range validator.Validate(obj) {
+		if f.Severity >= validator.SeverityWarning {
+			findings = append(findings, f.String())
+			severity = max(severity, f.Severity)
+		}
+	}
+	switch severity {
+	case validator.SeverityError:
+		result.Status = StatusFail
+	case validator.SeverityWarning:
+		result.Status = StatusWarn
+	}
+	result.Message = strings.Join(findings, "; ")
+	return result
+}
+
+// checkResponse checks the media type and CORS header of a GET of path.
+//
+// https://datatracker.ietf.org/doc/html/rfc7480#section-5.6
+func (r *Runner) checkResponse(ctx context.Context, path string) []Result {
+	contentType := Result{Check: CheckContentType, Target: path}
+	cors := Result{Check: CheckCORS, Target: path}
+
+	resp, _, err := r.get(ctx, http.MethodGet, path, true)
+	if err != nil {
+		return []Result{fail(contentType, "%v", err), fail(cors, "%v", err)}
+	}
+
+	if mediaType := resp.Header.Get("Content-Type"); !isRDAPMediaType(mediaType) {
+		contentType = fail(contentType, "Content-Type is %q, expected %q", mediaType, rdapMediaType)
+	}
+	if resp.Header.Get("Access-Control-Allow-Origin") == "" {
+		cors.Status = StatusWarn
+		cors.Message = "missing Access-Control-Allow-Origin header"
+	}
+	return []Result{contentType, cors}
+}
+
+// checkHEAD checks a HEAD of path is answered like a GET, without body.
+//
+// https://datatracker.ietf.org/doc/html/rfc7480#section-4.1
+func (r *Runner) checkHEAD(ctx context.Context, path string) Result {
+	result := Result{Check: CheckHEAD, Target: path}
+
+	resp, body, err := r.get(ctx, http.MethodHead, path, true)
+	switch {
+	case err != nil:
+		return fail(result, "%v", err)
+	case resp.StatusCode != http.StatusOK:
+		return fail(result, "status is %s, expected 200", resp.Status)
+	case len(body) > 0:
+		return fail(result, "response has a body of %d bytes", len(body))
+	}
+	return result
+}
+
+// checkStatus checks a GET of path is answered with the status code want.
+//
+// https://datatracker.ietf.org/doc/html/rfc7480#section-5.3
+func (r *Runner) checkStatus(ctx context.Context, check, path string, want int) Result {
+	result := Result{Check: check, Target: path}
+
+	resp, _, err := r.get(ctx, http.MethodGet, path, true)
+	if err != nil {
+		return fail(result, "%v", err)
+	}
+	if resp.StatusCode != want {
+		return fail(result, "status is %s, expected %d", resp.Status, want)
+	}
+	return result
+}
+
+// checkHelp checks the help path returns a help response.
+//
+// https://datatracker.ietf.org/doc/html/rfc9083#section-7
+func (r *Runner) checkHelp(ctx context.Context) Result {
+	result := Result{Check: CheckHelp, Target: "help"}
+
+	resp, body, err := r.get(ctx, http.MethodGet, "help", true)
+	if err != nil {
+		return fail(result, "%v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fail(result, "status is %s, expected 200", resp.Status)
+	}
+
+	var help struct {
+		Conformance []string          `json:"rdapConformance"`
+		Notices     []openrdap.Notice `json:"notices"`
+	}
+	if err := json.Unmarshal(body, &help); err != nil {
+		return fail(result, "invalid help response: %v", err)
+	}
+	if len(help.Conformance) == 0 {
+		return fail(result, "help response has no rdapConformance")
+	}
+	if len(help.Notices) == 0 {
+		result.Status = StatusWarn
+		result.Message = "help response has no notices"
+	}
+	return result
+}
+
+// checkRedirect checks the Redirect query is answered with a redirect to an
+// absolute URL.
+//
+// https://datatracker.ietf.org/doc/html/rfc7480#section-5.2
+func (r *Runner) checkRedirect(ctx context.Context) Result {
+	if r.config.Redirect == nil {
+		return Result{Check: CheckRedirect, Status: StatusSkip, Message: "no redirect query configured"}
+	}
+	path := r.config.Redirect.path()
+	result := Result{Check: CheckRedirect, Target: path}
+
+	resp, _, err := r.get(ctx, http.MethodGet, path, false)
+	if err != nil {
+		return fail(result, "%v", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+	default:
+		return fail(result, "status is %s, expected a redirect", resp.Status)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	switch {
+	case err != nil:
+		return fail(result, "invalid Location header: %v", err)
+	case location.String() == "":
+		return fail(result, "missing Location header")
+	case !location.IsAbs():
+		result.Status = StatusWarn
+		result.Message = fmt.Sprintf("Location %q is not absolute", location)
+	}
+	return result
+}
+
+// get sends a request for path relative to the base URL and returns the
+// response with its body. Redirects are only followed if follow is set.
+func (r *Runner) get(ctx context.Context, method, path string, follow bool) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, r.config.BaseURL+path, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", rdapMediaType)
+	req.Header.Set("Origin", "https://conformance.invalid")
+
+	httpClient := r.client.HTTPClient()
+	if !follow {
+		noRedirect := *httpClient
+		noRedirect.CheckRedirect = func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+		httpClient = &noRedirect
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading response: %w", err)
+	}
+	return resp, body, nil
+}
+
+// notFoundQueries returns the configured NotFound queries, or queries for
+// names below the domains and beside the entities of Queries.
+func (r *Runner) notFoundQueries() []Query {
+	if len(r.config.NotFound) > 0 {
+		return r.config.NotFound
+	}
+
+	var queries []Query
+	seen := make(map[openrdap.RegistrySearchType]bool)
+	for _, q := range r.config.Queries {
+		if seen[q.Type] {
+			continue
+		}
+		switch q.Type {
+		case openrdap.DNS:
+			tld := q.Value[strings.LastIndex(q.Value, ".")+1:]
+			queries = append(queries, Query{q.Type, "rdap-conformance-not-found." + tld})
+		case openrdap.ENTITY:
+			queries = append(queries, Query{q.Type, "RDAP-CONFORMANCE-NOT-FOUND"})
+		default:
+			continue
+		}
+		seen[q.Type] = true
+	}
+	return queries
+}
+
+// malformedQueries returns the configured Malformed queries, or a malformed
+// query for each query type of Queries.
+func (r *Runner) malformedQueries() []Query {
+	if len(r.config.Malformed) > 0 {
+		return r.config.Malformed
+	}
+
+	var queries []Query
+	seen := make(map[string]bool)
+	for _, q := range r.config.Queries {
+		var malformed Query
+		switch q.Type {
+		case openrdap.DNS:
+			malformed = Query{q.Type, "a..b"}
+		case openrdap.IPv4, openrdap.IPv6:
+			malformed = Query{q.Type, "not-an-ip"}
+		case openrdap.ASN:
+			malformed = Query{q.Type, "not-a-number"}
+		default:
+			continue
+		}
+		if path := malformed.path(); !seen[path] {
+			seen[path] = true
+			queries = append(queries, malformed)
+		}
+	}
+	return queries
+}
+
+func fail(result Result, format string, args ...any) Result {
+	result.Status = StatusFail
+	result.Message = fmt.Sprintf(format, args...)
+	return result
+}
+
+// isRDAPMediaType reports whether the Content-Type header value is
+// application/rdap+json, ignoring parameters.
+func isRDAPMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == rdapMediaType
+}
@@ -0,0 +1,161 @@
+package conformance
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/perihwk/openrdap"
+)
+
+const exampleDomain = `{
+  "rdapConformance": ["rdap_level_0"],
+  "objectClassName": "domain",
+  "ldhName": "example.com",
+  "links": [{"rel": "self", "href": "https://rdap.example/domain/example.com"}]
+}`
+
+// newServer returns a server answering example.com and the help path. A
+// compliant server sets the RDAP media type and CORS header, answers
+// unknown objects with 404, malformed queries with 400 and redirects .org
+// domains.
+func newServer(compliant bool) *httptest.Server {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if compliant {
+			w.Header().Set("Content-Type", "application/rdap+json")
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Content-Type", "text/html")
+		}
+
+		var status int
+		var body string
+		switch path := r.URL.Path; {
+		case path == "/domain/example.com":
+			status, body = http.StatusOK, exampleDomain
+		case path == "/help":
+			status, body = http.StatusOK, `{"rdapConformance": ["rdap_level_0"], "notices": [{"title": "Help"}]}`
+		case !compliant:
+			// answers anything with the example domain
+			status, body = http.StatusOK, exampleDomain
+		case strings.Contains(path, ".."):
+			status, body = http.StatusBadRequest, `{"errorCode": 400, "title": "Bad Request"}`
+		case strings.HasSuffix(path, ".org"):
+			http.Redirect(w, r, "https://rdap.example.org"+path, http.StatusFound)
+			return
+		default:
+			status, body = http.StatusNotFound, `{"errorCode": 404, "title": "Not Found"}`
+		}
+
+		w.WriteHeader(status)
+		if r.Method != http.MethodHead {
+			w.Write([]byte(body))
+		}
+	})
+
+	if compliant {
+		return httptest.NewTLSServer(handler)
+	}
+	return httptest.NewServer(handler)
+}
+
+type result struct {
+	Check  string
+	Target string
+	Status Status
+}
+
+func runConformance(t *testing.T, server *httptest.Server, config Config) []result {
+	t.Helper()
+
+	client := openrdap.NewClient(server.Client(), nil)
+	config.BaseURL = server.URL
+	report, err := NewRunner(client, config).Run(context.Background())
+	if err != nil {
+		t.Fatalf("failed to run: %v", err)
+	}
+
+	var results []result
+	for _, r := range report.Results {
+		results = append(results, result{r.Check, r.Target, r.Status})
+	}
+	return results
+}
+
+func TestRunCompliant(t *testing.T) {
+	server := newServer(true)
+	defer server.Close()
+
+	results := runConformance(t, server, Config{
+		Queries:  []Query{{openrdap.DNS, "example.com"}},
+		Redirect: &Query{openrdap.DNS, "example.org"},
+	})
+
+	expected := []result{
+		{CheckHTTPS, server.URL + "/", StatusPass},
+		{CheckLookup, "domain/example.com", StatusPass},
+		{CheckContentType, "domain/example.com", StatusPass},
+		{CheckCORS, "domain/example.com", StatusPass},
+		{CheckHEAD, "domain/example.com", StatusPass},
+		{CheckNotFound, "domain/rdap-conformance-not-found.com", StatusPass},
+		{CheckBadRequest, "domain/a..b", StatusPass},
+		{CheckHelp, "help", StatusPass},
+		{CheckRedirect, "domain/example.org", StatusPass},
+	}
+	if diff := cmp.Diff(expected, results); diff != "" {
+		t.Errorf("unexpected results (-want +got):\n%s", diff)
+	}
+}
+
+func TestRunNonCompliant(t *testing.T) {
+	server := newServer(false)
+	defer server.Close()
+
+	results := runConformance(t, server, Config{
+		Queries: []Query{{openrdap.DNS, "example.com"}},
+	})
+
+	expected := []result{
+		{CheckHTTPS, server.URL + "/", StatusFail},
+		{CheckLookup, "domain/example.com", StatusPass},
+		{CheckContentType, "domain/example.com", StatusFail},
+		{CheckCORS, "domain/example.com", StatusWarn},
+		{CheckHEAD, "domain/example.com", StatusPass},
+		{CheckNotFound, "domain/rdap-conformance-not-found.com", StatusFail},
+		{CheckBadRequest, "domain/a..b", StatusFail},
+		{CheckHelp, "help", StatusPass},
+		{CheckRedirect, "", StatusSkip},
+	}
+	if diff := cmp.Diff(expected, results); diff != "" {
+		t.Errorf("unexpected results (-want +got):\n%s", diff)
+	}
+}
+
+func TestReport(t *testing.T) {
+	report := &Report{
+		BaseURL: "http://rdap.example/",
+		Results: []Result{
+			{Check: CheckHTTPS, Target: "http://rdap.example/", Status: StatusFail, Message: `scheme is "http"`},
+			{Check: CheckHelp, Target: "help"},
+		},
+	}
+
+	if !report.Failed() {
+		t.Error("expected report to have failed")
+	}
+
+	var b strings.Builder
+	if _, err := report.WriteTo(&b); err != nil {
+		t.Fatalf("failed to write report: %v", err)
+	}
+	expected := `fail https http://rdap.example/: scheme is "http"
+pass help help
+http://rdap.example/: 1 passed, 0 warnings, 1 failed, 0 skipped
+`
+	if b.String() != expected {
+		t.Errorf("unexpected report:\n%s", b.String())
+	}
+}
@@ -0,0 +1,181 @@
+package openrdap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Decode parses an RDAP response, choosing the type from its content:
+//
+//   - objects are decoded by objectClassName into *Domain, *Nameserver,
+//     *Entity, *IPNetwork or *Autnum, and into *RawObject for other classes
+//   - search results are decoded into *SearchResults
+//   - error responses are returned as a *ErrorResponse error
+func Decode(data []byte) (Object, error) {
+	var probe struct {
+		ObjectClassName *string          `json:"objectClassName"`
+		ErrorCode       *int             `json:"errorCode"`
+		Domains         *json.RawMessage `json:"domainSearchResults"`
+		Nameservers     *json.RawMessage `json:"nameserverSearchResults"`
+		Entities        *json.RawMessage `json:"entitySearchResults"`
+		IPNetworks      *json.RawMessage `json:"ipSearchResults"`
+		Autnums         *json.RawMessage `json:"autnumSearchResults"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("error parsing RDAP response: %w", err)
+	}
+
+	var result Object
+	switch {
+	case probe.ErrorCode != nil && probe.ObjectClassName == nil:
+		errResp := &ErrorResponse{}
+		if err := json.Unmarshal(data, errResp); err != nil {
+			return nil, fmt.Errorf("error parsing RDAP error response: %w", err)
+		}
+		return nil, errResp
+	case probe.ObjectClassName == nil:
+		if probe.Domains == nil && probe.Nameservers == nil && probe.Entities == nil &&
+			probe.IPNetworks == nil && probe.Autnums == nil {
+			return nil, ErrMissingObjectClassName
+		}
+		result = &SearchResults{}
+	default:
+		switch strings.ToLower(*probe.ObjectClassName) {
+		case "domain":
+			result = &Domain{}
+		case "nameserver":
+			result = &Nameserver{}
+		case "entity":
+			result = &Entity{}
+		case "ip network":
+			result = &IPNetwork{}
+		case "autnum":
+			result = &Autnum{}
+		default:
+			result = &RawObject{}
+		}
+	}
+
+	if err := json.Unmarshal(data, result); err != nil {
+		return nil, fmt.Errorf("error parsing RDAP response: %w", err)
+	}
+	return result, nil
+}
+
+// SearchResults is the response to an RDAP search. Only the results of the
+// searched object class are set.
+//
+// https://datatracker.ietf.org/doc/html/rfc7483#section-8
+type SearchResults struct {
+	Common
+	Conformance []string `json:"rdapConformance,omitempty"`
+	Notices     []Notice `json:"notices,omitempty"`
+	Links       []Link   `json:"links,omitempty"`
+
+	Domains     []Domain     `json:"domainSearchResults,omitempty"`
+	Nameservers []Nameserver `json:"nameserverSearchResults,omitempty"`
+	Entities    []Entity     `json:"entitySearchResults,omitempty"`
+	IPNetworks  []IPNetwork  `json:"ipSearchResults,omitempty"`
+	Autnums     []Autnum     `json:"autnumSearchResults,omitempty"`
+}
+
+// Objects returns every result, in the order domains, nameservers, entities,
+// IP networks and autnums.
+func (s *SearchResults) Objects() []Object {
+	var result []Object
+	for i := range s.Domains {
+		result = append(result, &s.Domains[i])
+	}
+	for i := range s.Nameservers {
+		result = append(result, &s.Nameservers[i])
+	}
+	for i := range s.Entities {
+		result = append(result, &s.Entities[i])
+	}
+	for i := range s.IPNetworks {
+		result = append(result, &s.IPNetworks[i])
+	}
+	for i := range s.Autnums {
+		result = append(result, &s.Autnums[i])
+	}
+	return result
+}
+
+// SearchResults is not an object class itself, only the notices and links of
+// the response are available through the Object interface. GetEntities
+// returns the entity search results.
+func (s *SearchResults) GetObjectClassName() string { return "" }
+func (s *SearchResults) GetHandle() string          { return "" }
+func (s *SearchResults) GetEntities() []Entity      { return s.Entities }
+func (s *SearchResults) GetEvents() []Event         { return nil }
+func (s *SearchResults) GetLinks() []Link           { return s.Links }
+func (s *SearchResults) GetRemarks() []Remark       { return nil }
+func (s *SearchResults) GetNotices() []Notice       { return s.Notices }
+func (s *SearchResults) GetStatus() []Status        { return nil }
+func (s *SearchResults) GetPort43() string          { return "" }
+func (s *SearchResults) GetExtensions() Extensions  { return nil }
+
+// RawObject holds an object of a class unknown to this package. The members
+// shared by all object classes are decoded, and the complete object is kept
+// in Raw.
+type RawObject struct {
+	Common
+	Conformance     []string `json:"rdapConformance,omitempty"`
+	ObjectClassName string   `json:"objectClassName,omitempty"`
+	Notices         []Notice `json:"notices,omitempty"`
+
+	Handle   string   `json:"handle,omitempty"`
+	Entities []Entity `json:"entities,omitempty"`
+	Status   []Status `json:"status,omitempty"`
+	Remarks  []Remark `json:"remarks,omitempty"`
+	Links    []Link   `json:"links,omitempty"`
+	Port43   string   `json:"port43,omitempty"`
+	Events   []Event  `json:"events,omitempty"`
+
+	// Extensions holds the members not listed above, including the members
+	// specific to the object class.
+	Extensions Extensions      `json:"-"`
+	Raw        json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON for RawObject to keep the raw JSON
+func (r *RawObject) UnmarshalJSON(data []byte) error {
+	type Alias RawObject
+	extensions, err := unmarshalObject(data, (*Alias)(r), reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+
+	r.Extensions = extensions
+	r.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// MarshalJSON for RawObject to return the raw JSON unchanged
+func (r RawObject) MarshalJSON() ([]byte, error) {
+	if r.Raw == nil {
+		type Alias RawObject
+		return marshalObject(Alias(r), r.Extensions)
+	}
+	return r.Raw, nil
+}
+
+func (r *RawObject) GetObjectClassName() string { return r.ObjectClassName }
+func (r *RawObject) GetHandle() string          { return r.Handle }
+func (r *RawObject) GetEntities() []Entity      { return r.Entities }
+func (r *RawObject) GetEvents() []Event         { return r.Events }
+func (r *RawObject) GetLinks() []Link           { return r.Links }
+func (r *RawObject) GetRemarks() []Remark       { return r.Remarks }
+func (r *RawObject) GetNotices() []Notice       { return r.Notices }
+func (r *RawObject) GetStatus() []Status        { return r.Status }
+func (r *RawObject) GetPort43() string          { return r.Port43 }
+func (r *RawObject) GetExtensions() Extensions  { return r.Extensions }
+
+// isErrorResponse reports whether err is an RDAP error response.
+func isErrorResponse(err error) bool {
+	var errResp *ErrorResponse
+	return errors.As(err, &errResp)
+}
@@ -0,0 +1,144 @@
+package openrdap
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		expectedType string
+	}{
+		{name: "Domain", path: "test/example_domain_perihwk.json", expectedType: "*openrdap.Domain"},
+		{name: "IPNetwork", path: "test/example_ip_8888.json", expectedType: "*openrdap.IPNetwork"},
+		{name: "Autnum", path: "test/example_asn_23552.json", expectedType: "*openrdap.Autnum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileData, err := os.ReadFile(tt.path)
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+
+			obj, err := Decode(fileData)
+			if err != nil {
+				t.Fatalf("failed to decode: %v", err)
+			}
+			if typ := fmt.Sprintf("%T", obj); typ != tt.expectedType {
+				t.Errorf("Expected %s, got %s", tt.expectedType, typ)
+			}
+		})
+	}
+}
+
+func TestDecodeSearchResults(t *testing.T) {
+	obj, err := Decode([]byte(`{
+  "rdapConformance": ["rdap_level_0"],
+  "domainSearchResults": [
+    {"objectClassName": "domain", "ldhName": "example.com"},
+    {"objectClassName": "domain", "ldhName": "example.net"}
+  ]
+}`))
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	results, ok := obj.(*SearchResults)
+	if !ok {
+		t.Fatalf("Expected *SearchResults, got %T", obj)
+	}
+	objects := results.Objects()
+	if len(objects) != 2 || objects[1].(*Domain).LDHName != "example.net" {
+		t.Errorf("unexpected search results %+v", objects)
+	}
+}
+
+func TestDecodeErrorResponse(t *testing.T) {
+	_, err := Decode([]byte(`{"errorCode": 404, "title": "Not Found", "description": ["The domain was not found."]}`))
+
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Fatalf("Expected *ErrorResponse, got %v", err)
+	}
+	if errResp.ErrorCode != 404 || errResp.Error() != "rdap error 404: Not Found: The domain was not found." {
+		t.Errorf("unexpected error %q", errResp.Error())
+	}
+}
+
+func TestDecodeRawObject(t *testing.T) {
+	input := `{"objectClassName":"example","handle":"EX-1","custom":{"a":1}}`
+	obj, err := Decode([]byte(input))
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	raw, ok := obj.(*RawObject)
+	if !ok {
+		t.Fatalf("Expected *RawObject, got %T", obj)
+	}
+	if raw.GetHandle() != "EX-1" || raw.GetObjectClassName() != "example" {
+		t.Errorf("unexpected object %+v", raw)
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if string(data) != input {
+		t.Errorf("Expected %s, got %s", input, data)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	if _, err := Decode([]byte(`{"handle": "X"}`)); !errors.Is(err, ErrMissingObjectClassName) {
+		t.Errorf("Expected ErrMissingObjectClassName, got %v", err)
+	}
+	if _, err := Decode([]byte(`["not", "an", "object"]`)); err == nil {
+		t.Error("Expected an error for a JSON array")
+	}
+}
+
+func TestGetRDAPInfoFromServerMismatch(t *testing.T) {
+	responses := map[string]struct {
+		status int
+		body   string
+	}{
+		"/entity/X":         {http.StatusOK, `{"objectClassName": "entity", "handle": "X"}`},
+		"/domain/not.found": {http.StatusNotFound, `{"errorCode": 404, "title": "Not Found"}`},
+	}
+	mockServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := responses[r.URL.Path]
+		w.WriteHeader(resp.status)
+		w.Write([]byte(resp.body))
+	}))
+	defer mockServer.Close()
+
+	client := &Client{
+		httpClient: mockServer.Client(),
+	}
+	ctx := context.Background()
+
+	// the server answers with an entity, whatever was asked for
+	rdapInfo, err := client.GetRDAPInfoFromServer(ctx, mockServer.URL+"/", "X", ENTITY)
+	if err != nil {
+		t.Fatalf("Failed to get RDAP info: %v", err)
+	}
+	if entity, ok := rdapInfo.(*Entity); !ok || entity.Handle != "X" {
+		t.Errorf("Expected entity X, got %+v", rdapInfo)
+	}
+
+	_, err = client.GetRDAPInfoFromServer(ctx, mockServer.URL+"/", "not.found", DNS)
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || errResp.ErrorCode != 404 {
+		t.Errorf("Expected a 404 error response, got %v", err)
+	}
+}
@@ -1,56 +1,61 @@
 package openrdap
 
+import (
+	"reflect"
+)
+
 // Domain represents information about a DNS name and point of delegation.
 //
 // Domain is a topmost RDAP response object.
 // https://tools.ietf.org/html/rfc7483
 type Domain struct {
 	Common
-	Conformance     []string `json:"rdapConformance"`
-	ObjectClassName string   `json:"objectClassName"`
+	Conformance     []string `json:"rdapConformance,omitempty"`
+	ObjectClassName string   `json:"objectClassName,omitempty"`
 
-	Notices []Notice `json:"notices"`
+	Notices []Notice `json:"notices,omitempty"`
 
-	Handle      string `json:"handle"`
-	LDHName     string `json:"ldhName"`
-	UnicodeName string `json:"unicodename"`
+	Handle      string `json:"handle,omitempty"`
+	LDHName     string `json:"ldhName,omitempty"`
+	UnicodeName string `json:"unicodeName,omitempty"`
 
-	Variants    []Variant    `json:"variants"`
-	Nameservers []Nameserver `json:"nameservers"`
+	Variants    []Variant    `json:"variants,omitempty"`
+	Nameservers []Nameserver `json:"nameservers,omitempty"`
 
-	SecureDNS *SecureDNS `json:"secureDNS"`
+	SecureDNS *SecureDNS `json:"secureDNS,omitempty"`
 
-	Entities []Entity `json:"entities"`
+	Entities []Entity `json:"entities,omitempty"`
 
 	// Status indicates the state of a registered object.
-	// Embedded (no separate type):
-	// Status []string
 	//
 	// https://tools.ietf.org/html/rfc7483#section-4.6
-	Status []string `json:"status"`
+	Status []Status `json:"status,omitempty"`
+
+	PublicIDs []PublicID `json:"publicIds,omitempty"`
+	Remarks   []Remark   `json:"remarks,omitempty"`
 
-	PublicIDs []PublicID `json:"publicIds"`
-	Remarks   []Remark   `json:"remarks"`
+	Links   []Link     `json:"links,omitempty"`
+	Port43  string     `json:"port43,omitempty"`
+	Events  []Event    `json:"events,omitempty"`
+	Network *IPNetwork `json:"network,omitempty"`
 
-	Links   []Link     `json:"links"`
-	Port43  string     `json:"port43"`
-	Events  []Event    `json:"events"`
-	Network *IPNetwork `json:"network"`
+	// Extensions holds the members of extensions without a field above.
+	Extensions Extensions `json:"-"`
 }
 
 // Variant is a subfield of Domain.
 type Variant struct {
 	Common
-	Relation     []string
-	IDNTable     string `json:"idnTable"`
-	VariantNames []VariantName
+	Relation     []string      `json:"relation,omitempty"`
+	IDNTable     string        `json:"idnTable,omitempty"`
+	VariantNames []VariantName `json:"variantNames,omitempty"`
 }
 
 // VariantName is a subfield of Variant.
 type VariantName struct {
 	Common
-	LDHName     string `json:"ldhName"`
-	UnicodeName string
+	LDHName     string `json:"ldhName,omitempty"`
+	UnicodeName string `json:"unicodeName,omitempty"`
 }
 
 // SecureDNS is ia subfield of Domain.
@@ -58,9 +63,9 @@ type SecureDNS struct {
 	Common
 	ZoneSigned       bool      `json:"zoneSigned"`
 	DelegationSigned bool      `json:"delegationSigned"`
-	MaxSigLife       uint64    `json:"maxSigLife"`
-	DS               []DSData  `json:"dsData"`
-	Keys             []KeyData `json:"keyData"`
+	MaxSigLife       uint64    `json:"maxSigLife,omitempty"`
+	DS               []DSData  `json:"dsData,omitempty"`
+	Keys             []KeyData `json:"keyData,omitempty"`
 }
 
 // DSData is a subfield of Domain.
@@ -68,48 +73,32 @@ type DSData struct {
 	Common
 	KeyTag     uint64 `json:"keyTag"`
 	Algorithm  uint8  `json:"algorithm"`
-	Digest     string `json:"digest"`
+	Digest     string `json:"digest,omitempty"`
 	DigestType uint8  `json:"digestType"`
 
-	Events []Event `json:"events"`
-	Links  []Link  `json:"links"`
+	Events []Event `json:"events,omitempty"`
+	Links  []Link  `json:"links,omitempty"`
 }
 
 type KeyData struct {
 	Flags     uint16 `json:"flags"`
 	Protocol  uint8  `json:"protocol"`
 	Algorithm uint8  `json:"algorithm"`
-	PublicKey string `json:"publicKey"`
+	PublicKey string `json:"publicKey,omitempty"`
 
-	Events []Event `json:"events"`
-	Links  []Link  `json:"links"`
+	Events []Event `json:"events,omitempty"`
+	Links  []Link  `json:"links,omitempty"`
 }
 
+// GetEventByName returns the first event of the domain with the given action.
 func (d *Domain) GetEventByName(name string) *Event {
-	for _, event := range d.Events {
-		if event.Action == name {
-			return &event
-		}
-	}
-	return nil
+	return EventByAction(d, name)
 }
 
+// GetEntityFromRole returns the first entity of the domain with the given
+// role, searching nested entities at any depth.
 func (d *Domain) GetEntityFromRole(role string) *Entity {
-	for _, entity := range d.Entities {
-		for _, entityRole := range entity.Roles {
-			if entityRole == role {
-				return &entity
-			}
-		}
-		for _, nestedEntity := range entity.Entities {
-			for _, nestedEntityRole := range nestedEntity.Roles {
-				if nestedEntityRole == role {
-					return &nestedEntity
-				}
-			}
-		}
-	}
-	return nil
+	return EntityByRole(d, role)
 }
 
 func (d *Domain) GetRegistrarURL() string {
@@ -128,3 +117,59 @@ func (d *Domain) GetNameServersDNS() []string {
 	}
 	return nameservers
 }
+
+// HasStatus reports whether the domain has the given status.
+func (d *Domain) HasStatus(status Status) bool {
+	return HasStatus(d, status)
+}
+
+// IsTransferLocked reports whether transfers of the domain are prohibited by
+// the registrar, the registry, or both.
+func (d *Domain) IsTransferLocked() bool {
+	return hasStatus(d.Status, StatusClientTransferProhibited, StatusServerTransferProhibited, StatusTransferProhibited)
+}
+
+// IsUpdateLocked reports whether updates of the domain are prohibited.
+func (d *Domain) IsUpdateLocked() bool {
+	return hasStatus(d.Status, StatusClientUpdateProhibited, StatusServerUpdateProhibited, StatusUpdateProhibited)
+}
+
+// IsDeleteLocked reports whether deletion of the domain is prohibited.
+func (d *Domain) IsDeleteLocked() bool {
+	return hasStatus(d.Status, StatusClientDeleteProhibited, StatusServerDeleteProhibited, StatusDeleteProhibited)
+}
+
+// IsOnHold reports whether the domain is withheld from the DNS by the
+// registrar or the registry.
+func (d *Domain) IsOnHold() bool {
+	return hasStatus(d.Status, StatusClientHold, StatusServerHold)
+}
+
+// IsPendingDelete reports whether the domain is about to be deleted.
+func (d *Domain) IsPendingDelete() bool {
+	return hasStatus(d.Status, StatusPendingDelete)
+}
+
+// IsInRedemption reports whether the domain has been deleted and can still be
+// restored by the registrar, including while a restore is pending.
+func (d *Domain) IsInRedemption() bool {
+	return hasStatus(d.Status, StatusRedemptionPeriod, StatusPendingRestore)
+}
+
+// UnmarshalJSON for Domain to keep extension members
+func (d *Domain) UnmarshalJSON(data []byte) error {
+	type Alias Domain
+	extensions, err := unmarshalObject(data, (*Alias)(d), reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+
+	d.Extensions = extensions
+	return nil
+}
+
+// MarshalJSON for Domain to write back extension members
+func (d Domain) MarshalJSON() ([]byte, error) {
+	type Alias Domain
+	return marshalObject(Alias(d), d.Extensions)
+}
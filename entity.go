@@ -1,8 +1,9 @@
 package openrdap
 
 import (
-	"encoding/json"
 	"fmt"
+	"reflect"
+	"strings"
 )
 
 // Entity represents information of an organisation or person.
@@ -11,23 +12,26 @@ import (
 // https://datatracker.ietf.org/doc/html/rfc7483#section-5.1
 type Entity struct {
 	Common
-	Conformance     []string `json:"rdapConformance"`
-	ObjectClassName string
-	Notices         []Notice
-
-	Handle       string
-	VCards       []VCard
-	Roles        []string
-	PublicIDs    []PublicID
-	Entities     []Entity
-	Remarks      []Remark
-	Links        []Link
-	Events       []Event
-	AsEventActor []Event
-	Status       []string
-	Port43       string
-	Networks     []IPNetwork
-	Autnums      []Autnum
+	Conformance     []string `json:"rdapConformance,omitempty"`
+	ObjectClassName string   `json:"objectClassName,omitempty"`
+	Notices         []Notice `json:"notices,omitempty"`
+
+	Handle       string      `json:"handle,omitempty"`
+	VCards       []VCard     `json:"-"`
+	Roles        []string    `json:"roles,omitempty"`
+	PublicIDs    []PublicID  `json:"publicIds,omitempty"`
+	Entities     []Entity    `json:"entities,omitempty"`
+	Remarks      []Remark    `json:"remarks,omitempty"`
+	Links        []Link      `json:"links,omitempty"`
+	Events       []Event     `json:"events,omitempty"`
+	AsEventActor []Event     `json:"asEventActor,omitempty"`
+	Status       []Status    `json:"status,omitempty"`
+	Port43       string      `json:"port43,omitempty"`
+	Networks     []IPNetwork `json:"networks,omitempty"`
+	Autnums      []Autnum    `json:"autnums,omitempty"`
+
+	// Extensions holds the members of extensions without a field above.
+	Extensions Extensions `json:"-"`
 }
 
 // UnmarshalJSON for Entity to handle custom vCard processing
@@ -41,12 +45,13 @@ func (e *Entity) UnmarshalJSON(data []byte) error {
 		Alias: (*Alias)(e),
 	}
 
-	if err := json.Unmarshal(data, aux); err != nil {
+	extensions, err := unmarshalObject(data, aux, reflect.TypeOf(Alias{}), "vcardArray")
+	if err != nil {
 		return fmt.Errorf("failed to unmarshal entity: %w", err)
 	}
+	e.Extensions = extensions
 
 	// Process the rawVCard data into the structured VCard type
-	var err error
 	parsedJCard, err := parseJCard(aux.RawVCard)
 	if err != nil {
 		return err
@@ -56,3 +61,57 @@ func (e *Entity) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+
+// MarshalJSON for Entity to encode the first vCard as the jCard in
+// vcardArray
+func (e Entity) MarshalJSON() ([]byte, error) {
+	type Alias Entity
+	aux := struct {
+		Alias
+		RawVCard []interface{} `json:"vcardArray,omitempty"`
+	}{
+		Alias: Alias(e),
+	}
+
+	if len(e.VCards) > 0 && !e.VCards[0].isZero() {
+		aux.RawVCard = encodeJCard(e.VCards[0])
+	}
+
+	return marshalObject(aux, e.Extensions)
+}
+
+// VCard returns the first vCard of the entity, or an empty VCard if it has
+// none.
+func (e *Entity) VCard() VCard {
+	if len(e.VCards) == 0 {
+		return VCard{}
+	}
+	return e.VCards[0]
+}
+
+// HasStatus reports whether the entity has the given status.
+func (e *Entity) HasStatus(status Status) bool {
+	return HasStatus(e, status)
+}
+
+// GetEventByName returns the first event of the entity with the given action.
+func (e *Entity) GetEventByName(name string) *Event {
+	return EventByAction(e, name)
+}
+
+// GetEntityFromRole returns the first entity of the entity with the given
+// role, searching nested entities at any depth.
+func (e *Entity) GetEntityFromRole(role string) *Entity {
+	return EntityByRole(e, role)
+}
+
+// HasRole reports whether the entity has the given role, e.g. "abuse". Roles
+// are compared case-insensitively.
+func (e *Entity) HasRole(role string) bool {
+	for _, r := range e.Roles {
+		if strings.EqualFold(r, role) {
+			return true
+		}
+	}
+	return false
+}
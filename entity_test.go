@@ -0,0 +1,107 @@
+package openrdap
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// roundTrip unmarshals the sample file into v, marshals it, and unmarshals the
+// result into a new value of the same type.
+func roundTrip[T any](t *testing.T, path string) (T, T, []byte) {
+	t.Helper()
+
+	fileData, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var original, decoded T
+	if err := json.Unmarshal(fileData, &original); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", path, err)
+	}
+	data, err := json.Marshal(original)
+	if err != nil {
+		t.Fatalf("failed to marshal %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("failed to unmarshal the marshalled %s: %v", path, err)
+	}
+	return original, decoded, data
+}
+
+func TestMarshalJSONRoundTrip(t *testing.T) {
+	t.Run("Domain", func(t *testing.T) {
+		original, decoded, data := roundTrip[Domain](t, "test/example_domain_perihwk.json")
+		if !cmp.Equal(original, decoded) {
+			t.Errorf("round trip changed the domain: %s", cmp.Diff(original, decoded))
+		}
+		if !strings.Contains(string(data), `"vcardArray"`) || strings.Contains(string(data), `"VCards"`) {
+			t.Errorf("expected entities to be encoded with vcardArray: %s", data)
+		}
+	})
+	t.Run("IPNetwork", func(t *testing.T) {
+		original, decoded, _ := roundTrip[IPNetwork](t, "test/example_ip_8888.json")
+		if !cmp.Equal(original, decoded) {
+			t.Errorf("round trip changed the network: %s", cmp.Diff(original, decoded))
+		}
+	})
+	t.Run("Autnum", func(t *testing.T) {
+		original, decoded, _ := roundTrip[Autnum](t, "test/example_asn_23552.json")
+		if !cmp.Equal(original, decoded) {
+			t.Errorf("round trip changed the autnum: %s", cmp.Diff(original, decoded))
+		}
+	})
+}
+
+func TestEntityMarshalJSON(t *testing.T) {
+	for _, path := range []string{"test/jcard/example.json", "test/jcard/example_label.json"} {
+		t.Run(path, func(t *testing.T) {
+			fileData, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+
+			var entity Entity
+			if err := json.Unmarshal([]byte(`{"objectClassName": "entity", "handle": "SP-1", "vcardArray": `+string(fileData)+`}`), &entity); err != nil {
+				t.Fatalf("failed to unmarshal entity: %v", err)
+			}
+			data, err := json.Marshal(&entity)
+			if err != nil {
+				t.Fatalf("failed to marshal entity: %v", err)
+			}
+
+			var decoded Entity
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("failed to unmarshal entity: %v", err)
+			}
+			if !cmp.Equal(entity, decoded) {
+				t.Errorf("round trip changed the entity: %s", cmp.Diff(entity, decoded))
+			}
+		})
+	}
+
+	data, err := json.Marshal(Entity{Handle: "X", VCards: []VCard{{FullName: "Example", Email: "abuse@example.com"}}})
+	if err != nil {
+		t.Fatalf("failed to marshal entity: %v", err)
+	}
+	expected := `{"handle":"X","vcardArray":["vcard",[["version",{},"text","4.0"],["fn",{},"text","Example"],["email",{},"text","abuse@example.com"]]]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, data)
+	}
+}
+
+func TestEntityVCard(t *testing.T) {
+	var entity Entity
+	if vcard := entity.VCard(); !cmp.Equal(vcard, VCard{}) {
+		t.Errorf("expected empty vCard, got %+v", vcard)
+	}
+
+	entity.VCards = []VCard{{FullName: "Example"}}
+	if vcard := entity.VCard(); vcard.FullName != "Example" {
+		t.Errorf("expected first vCard, got %+v", vcard)
+	}
+}
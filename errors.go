@@ -2,8 +2,39 @@ package openrdap
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 )
 
 var (
 	ErrInvalidJCard = errors.New("invalid jCard properties format")
+
+	// ErrMissingObjectClassName is returned by Decode for JSON objects which
+	// are neither RDAP objects, search results nor error responses.
+	ErrMissingObjectClassName = errors.New("missing objectClassName")
 )
+
+// ErrorResponse is an RDAP error response, returned as error by Decode and
+// the Client.
+//
+// https://datatracker.ietf.org/doc/html/rfc7483#section-6
+type ErrorResponse struct {
+	Common
+	Conformance []string `json:"rdapConformance,omitempty"`
+	Notices     []Notice `json:"notices,omitempty"`
+
+	ErrorCode   int      `json:"errorCode"`
+	Title       string   `json:"title,omitempty"`
+	Description []string `json:"description,omitempty"`
+}
+
+func (e *ErrorResponse) Error() string {
+	msg := fmt.Sprintf("rdap error %d", e.ErrorCode)
+	if e.Title != "" {
+		msg += ": " + e.Title
+	}
+	if len(e.Description) > 0 {
+		msg += ": " + strings.Join(e.Description, " ")
+	}
+	return msg
+}
@@ -7,13 +7,54 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
+	"text/template"
 	"time"
 
 	"github.com/perihwk/openrdap"
 	"github.com/perihwk/openrdap/bootstrap"
+	"github.com/perihwk/openrdap/render"
 )
 
+// domainTemplate lists the registration data of a phishing domain.
+var domainTemplate = template.Must(render.Parse("domain", `	RegistryDomainID: {{.Handle}}
+	DomainName: {{.LDHName}}
+	CreatedDate: {{event . "registration"}}
+	UpdatedDate: {{event . "last changed"}}
+	RegistrarExpirationDate: {{event . "expiration"}}
+	DomainAgeDays: {{age .}}
+	RegistrarWhoisServer: {{.Port43}}
+	NameServer: {{join .GetNameServersDNS ", "}}
+	DomainStatus: {{join .Status ", "}}
+{{- with entity . "registrar"}}
+	Registrar: {{(vcard .).FullName}}
+	RegistrarIanaID: {{.Handle}}
+	RegistrarURL: {{$.GetRegistrarURL}}
+{{- end}}
+{{- with entity . "abuse"}}
+	RegistrarAbuseContactEmail: {{(vcard .).Email}}
+	RegistrarAbuseContactPhone: {{tel (vcard .).Voice}}
+{{- end}}
+{{- with entity . "registrant"}}
+	RegistrantOrganization: {{(vcard .).Org}}
+	RegistrantState: {{(vcard .).Address.Region}}
+	RegistrantCountry: {{(vcard .).Address.Country}}
+	RegistrantEmail: {{(vcard .).Email}}
+{{- end}}
+{{- with entity . "administrative"}}
+	AdminOrganization: {{(vcard .).Org}}
+	AdminState: {{(vcard .).Address.Region}}
+	AdminCountry: {{(vcard .).Address.Country}}
+	AdminEmail: {{(vcard .).Email}}
+{{- end}}
+{{- with entity . "technical"}}
+	TechOrganization: {{(vcard .).Org}}
+	TechState: {{(vcard .).Address.Region}}
+	TechCountry: {{(vcard .).Address.Country}}
+	TechEmail: {{(vcard .).Email}}
+{{- end}}`))
+
 func main() {
 
 	ctx := context.Background()
@@ -68,62 +109,9 @@ func main() {
 			fmt.Println("BIG ASS ERROR: ", err)
 			continue
 		}
-		fmt.Printf("\tRegistryDomainID: %s\n", domainInfo.Handle)
-		fmt.Printf("\tDomainName: %s\n", domainInfo.LDHName)
-		if event := domainInfo.GetEventByName("registration"); event != nil {
-			fmt.Printf("\tCreatedDate: %s\n", event.Date)
-		}
-		if event := domainInfo.GetEventByName("last changed"); event != nil {
-			fmt.Printf("\tUpdatedDate: %s\n", event.Date)
-		}
-		if event := domainInfo.GetEventByName("expiration"); event != nil {
-			fmt.Printf("\tRegistrarExpirationDate: %s\n", event.Date)
-		}
-		fmt.Printf("\tRegistrarWhoisServer: %s\n", domainInfo.Port43)
-		fmt.Printf("\tNameServer: %s\n", domainInfo.GetNameServersDNS())
-		fmt.Printf("\tDomainStatus: %s\n", domainInfo.Status)
-
-		registrar := domainInfo.GetEntityFromRole("registrar")
-		if registrar != nil {
-			fmt.Printf("\tRegistrar: %s\n", registrar.VCards[0].FullName)
-			fmt.Printf("\tRegistrarIanaID: %s\n", registrar.Handle)
-		}
-
-		abuse := domainInfo.GetEntityFromRole("abuse")
-		if abuse != nil {
-			fmt.Printf("\tRegistrarAbuseContactEmail: %s\n", abuse.VCards[0].Email)
-			fmt.Printf("\tRegistrarAbuseContactPhone: %s\n", abuse.VCards[0].Telephone)
-		}
-
-		registrarURL := domainInfo.GetRegistrarURL()
-		if registrar != nil {
-			fmt.Printf("\tRegistrarURL: %s\n", registrarURL)
+		if err := render.Template(os.Stdout, domainTemplate, domainInfo); err != nil {
+			fmt.Println("Error:", err)
 		}
-
-		registrantEntity := domainInfo.GetEntityFromRole("registrant")
-		if registrantEntity != nil {
-			fmt.Printf("\tRegistrantOrganization: %s\n", registrantEntity.VCards[0].Org)
-			fmt.Printf("\tRegistrantState: %+v\n", registrantEntity.VCards[0].Address)
-			fmt.Printf("\tRegistrantCountry: %+v\n", registrantEntity.VCards[0].Address)
-			fmt.Printf("\tRegistrantEmail: %s\n", registrantEntity.VCards[0].Email)
-		}
-
-		adminEntity := domainInfo.GetEntityFromRole("administrative")
-		if adminEntity != nil {
-			fmt.Printf("\tAdminOrganization: %v\n", adminEntity.VCards[0].Org)
-			fmt.Printf("\tAdminState: %v\n", adminEntity.VCards[0].Address.Region)
-			fmt.Printf("\tAdminCountry: %v\n", adminEntity.VCards[0].Address.Country)
-			fmt.Printf("\tAdminEmail: %v\n", adminEntity.VCards[0].Email)
-		}
-
-		techEntity := domainInfo.GetEntityFromRole("technical")
-		if techEntity != nil {
-			fmt.Printf("\tTechOrganization: %v\n", techEntity.VCards[0].Org)
-			fmt.Printf("\tTechState: %v\n", techEntity.VCards[0].Address.Region)
-			fmt.Printf("\tTechCountry: %v\n", techEntity.VCards[0].Address.Country)
-			fmt.Printf("\tTechEmail: %v\n", techEntity.VCards[0].Email)
-		}
-
 	}
 
 	// Check for any errors encountered during scanning
@@ -6,10 +6,16 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/netip"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/perihwk/openrdap"
 	"github.com/perihwk/openrdap/bootstrap"
+	"github.com/perihwk/openrdap/formatter"
+	"github.com/perihwk/openrdap/render"
+	"github.com/perihwk/openrdap/validator"
 )
 
 func main() {
@@ -17,6 +23,18 @@ func main() {
 	serviceRegistryURL := flag.String("service-registry-url", "", "The URL of the service registry (optional)")
 	query := flag.String("query", "", "Name to query")
 	registryType := flag.String("registry-type", "dns", "Type of registry to query (dns, ipv4, ipv6, asn)")
+	cacheDir := flag.String("cache-dir", "", "Directory to cache bootstrap registries in (optional)")
+	supplementary := flag.String("supplementary", "", "Supplementary bootstrap file for ccTLDs missing from IANA, or \"default\" for the embedded list (optional)")
+	mirrorDir := flag.String("mirror", "", "Write all bootstrap registries to this directory and exit (optional)")
+	diffOld := flag.String("diff", "", "Compare this bootstrap file with -diff-new, or with the current registry of -registry-type, and exit (optional)")
+	diffNew := flag.String("diff-new", "", "Newer bootstrap file to compare with -diff (optional)")
+	servedBy := flag.String("served-by", "", "List the TLDs, networks and ASNs the bootstrap assigns to this RDAP base URL and exit (optional)")
+	snapshot := flag.String("snapshot", "", "Use the embedded bootstrap snapshot as initial data or as fallback (initial, fallback)")
+	validate := flag.Bool("validate", false, "Check domain responses against RFC 9083 and the ICANN gTLD RDAP profile (optional)")
+	hydrate := flag.Int("hydrate", 0, "Fetch stub nameservers and entities from their self links with this many concurrent requests (optional)")
+	format := flag.String("format", "whois", "Output format (whois, summary, table, csv, json, json-compact)")
+	columns := flag.String("columns", "", "Comma separated columns of the table and csv formats (optional, default "+strings.Join(render.DefaultColumns, ",")+")")
+	tmplText := flag.String("template", "", "text/template to render the response with, overrides -format (optional)")
 
 	// Parse command-line flags
 	flag.Parse()
@@ -33,9 +51,54 @@ func main() {
 			ExpectContinueTimeout: 1 * time.Second,
 		},
 	}
-	bClient := bootstrap.NewBootstrapClient(httpClient, *serviceRegistryURL)
+	var bootstrapOpts []bootstrap.Option
+	if *cacheDir != "" {
+		bootstrapOpts = append(bootstrapOpts, bootstrap.WithCacheDir(*cacheDir))
+	}
+	switch *snapshot {
+	case "":
+	case "initial":
+		bootstrapOpts = append(bootstrapOpts, bootstrap.WithSnapshot())
+	case "fallback":
+		bootstrapOpts = append(bootstrapOpts, bootstrap.WithSnapshotFallback())
+	default:
+		fmt.Println("Error: invalid snapshot mode", *snapshot)
+		flag.Usage()
+		return
+	}
+	switch *supplementary {
+	case "":
+	case "default":
+		bootstrapOpts = append(bootstrapOpts, bootstrap.WithSupplementary(nil))
+	default:
+		registry, err := bootstrap.LoadRegistryFile(*supplementary)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+		bootstrapOpts = append(bootstrapOpts, bootstrap.WithSupplementary(registry))
+	}
+	bClient := bootstrap.NewBootstrapClient(httpClient, *serviceRegistryURL, bootstrapOpts...)
 
-	rdapClient := openrdap.NewClient(httpClient, bClient)
+	var clientOpts []openrdap.Option
+	if *hydrate > 0 {
+		clientOpts = append(clientOpts, openrdap.WithHydration(*hydrate))
+	}
+	rdapClient := openrdap.NewClient(httpClient, bClient, clientOpts...)
+
+	if *mirrorDir != "" {
+		if err := bClient.WriteMirror(ctx, *mirrorDir); err != nil {
+			fmt.Println("Error:", err)
+		}
+		return
+	}
+
+	if *servedBy != "" {
+		if err := printServedResources(ctx, bClient, *servedBy); err != nil {
+			fmt.Println("Error:", err)
+		}
+		return
+	}
 
 	var regType bootstrap.RegistryType
 	if err := regType.Set(*registryType); err != nil {
@@ -44,18 +107,141 @@ func main() {
 		return
 	}
 
+	if *diffOld != "" {
+		if err := printRegistryDiff(ctx, bClient, regType, *diffOld, *diffNew); err != nil {
+			fmt.Println("Error:", err)
+		}
+		return
+	}
+
 	switch regType {
 	case bootstrap.DNS:
 		domain, err := rdapClient.GetRDAPFromDomain(ctx, *query)
 		if err != nil {
 			fmt.Println(err)
+			return
+		}
+		if err := printObject(domain, *format, *columns, *tmplText); err != nil {
+			fmt.Println("Error:", err)
+		}
+		if *validate {
+			for _, finding := range validator.Validate(domain, validator.WithGTLDProfile()) {
+				fmt.Println(finding)
+			}
+		}
+	case bootstrap.IPv4, bootstrap.IPv6:
+		var network *openrdap.IPNetwork
+		var err error
+		if prefix, prefixErr := netip.ParsePrefix(*query); prefixErr == nil {
+			network, err = rdapClient.GetRDAPFromIPPrefix(ctx, prefix)
+		} else {
+			network, err = rdapClient.GetRDAPFromIP(ctx, *query)
+		}
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if err := printObject(network, *format, *columns, *tmplText); err != nil {
+			fmt.Println("Error:", err)
 		}
-		openrdap.PrintDomainRDAP(domain)
 	case bootstrap.ASN:
 		autnum, err := rdapClient.GetRDAPFromAutnum(ctx, *query)
 		if err != nil {
 			fmt.Println(err)
+			return
 		}
-		openrdap.PrintAutnumRDAP(autnum)
+		if err := printObject(autnum, *format, *columns, *tmplText); err != nil {
+			fmt.Println("Error:", err)
+		}
+	}
+}
+
+// printObject writes obj to stdout in format, or with tmplText if it is set.
+func printObject(obj openrdap.Object, format, columns, tmplText string) error {
+	if tmplText != "" {
+		tmpl, err := render.Parse("template", tmplText)
+		if err != nil {
+			return err
+		}
+		return render.Template(os.Stdout, tmpl, obj)
+	}
+
+	var names []string
+	if columns != "" {
+		names = strings.Split(columns, ",")
+	}
+	cols, err := render.Columns(names...)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "whois":
+		return formatter.WriteWHOIS(os.Stdout, obj)
+	case "summary":
+		return render.Summary(os.Stdout, obj)
+	case "table":
+		return render.Table(os.Stdout, cols, obj)
+	case "csv":
+		return render.CSV(os.Stdout, cols, obj)
+	case "json":
+		return render.JSON(os.Stdout, obj, true)
+	case "json-compact":
+		return render.JSON(os.Stdout, obj, false)
+	default:
+		return fmt.Errorf("invalid format %q", format)
+	}
+}
+
+// printRegistryDiff prints the services added (+), removed (-) and changed (~)
+// between the bootstrap file oldPath and newPath, or the current registry if
+// newPath is empty.
+func printRegistryDiff(ctx context.Context, bClient *bootstrap.Client, regType bootstrap.RegistryType, oldPath, newPath string) error {
+	old, err := bootstrap.LoadRegistryFile(oldPath)
+	if err != nil {
+		return err
 	}
+
+	var new *bootstrap.Registry
+	if newPath != "" {
+		new, err = bootstrap.LoadRegistryFile(newPath)
+	} else {
+		new, err = bClient.FetchRegistryByType(ctx, regType, true)
+	}
+	if err != nil {
+		return err
+	}
+
+	diff := bootstrap.Diff(old, new)
+	fmt.Printf("Publication: %s -> %s\n", diff.OldPublication, diff.NewPublication)
+	for _, c := range diff.Added {
+		fmt.Printf("+ %s %s\n", c.Key, c.NewURLs)
+	}
+	for _, c := range diff.Removed {
+		fmt.Printf("- %s %s\n", c.Key, c.OldURLs)
+	}
+	for _, c := range diff.Changed {
+		fmt.Printf("~ %s %s -> %s\n", c.Key, c.OldURLs, c.NewURLs)
+	}
+	return nil
+}
+
+// printServedResources prints everything the bootstrap registries assign to
+// the RDAP server at baseURL.
+func printServedResources(ctx context.Context, bClient *bootstrap.Client, baseURL string) error {
+	if err := bClient.FetchAllRegistries(ctx); err != nil {
+		return err
+	}
+
+	served := bClient.ReverseIndex().Lookup(baseURL)
+	if served == nil {
+		return fmt.Errorf("%s is not referenced by the bootstrap registries", baseURL)
+	}
+
+	fmt.Printf("Server: %s\n", served.BaseURL)
+	fmt.Printf("Domains: %v\n", served.Domains)
+	fmt.Printf("IPv4: %v\n", served.IPv4)
+	fmt.Printf("IPv6: %v\n", served.IPv6)
+	fmt.Printf("ASNs: %v\n", served.ASNs)
+	return nil
 }
@@ -0,0 +1,244 @@
+package openrdap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrUnknownExtension is returned when no decoder is registered for an
+// extension.
+var ErrUnknownExtension = errors.New("unknown extension")
+
+// Extensions holds the members of an object which are not part of the RDAP
+// core model, such as "arin_originas0_originautnums", keyed by member name.
+// Values are kept as raw JSON, and are written back unchanged when the
+// object is marshalled.
+//
+// https://datatracker.ietf.org/doc/html/rfc9083#section-2.1
+type Extensions map[string]json.RawMessage
+
+// Members returns the members of the extension with identifier id, i.e. the
+// members named "<id>_...". It returns nil if there are none.
+func (e Extensions) Members(id string) Extensions {
+	var result Extensions
+	for name, value := range e {
+		if strings.HasPrefix(name, id+"_") {
+			if result == nil {
+				result = make(Extensions)
+			}
+			result[name] = value
+		}
+	}
+	return result
+}
+
+// Decode decodes the members of the extension with identifier id using the
+// decoder registered for it. It returns ErrUnknownExtension if there is no
+// decoder for id.
+func (e Extensions) Decode(id string) (any, error) {
+	extensionsMu.RLock()
+	decode, ok := extensionDecoders[id]
+	extensionsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownExtension, id)
+	}
+
+	data, err := decode(e.Members(id))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding extension %s: %w", id, err)
+	}
+	return data, nil
+}
+
+// An ExtensionDecoder decodes the members of an extension into typed data.
+// members only holds the members of the extension, and is nil if the object
+// has none of them.
+type ExtensionDecoder func(members Extensions) (any, error)
+
+var (
+	extensionsMu      sync.RWMutex
+	extensionDecoders = make(map[string]ExtensionDecoder)
+)
+
+// RegisterExtension registers the decoder for the extension with identifier
+// id, as listed in rdapConformance, e.g. "arin_originas0". A decoder
+// registered earlier for id is replaced.
+func RegisterExtension(id string, decode ExtensionDecoder) {
+	extensionsMu.Lock()
+	defer extensionsMu.Unlock()
+	extensionDecoders[id] = decode
+}
+
+// DecodeExtension decodes the extension with identifier id of o, see
+// Extensions.Decode.
+func DecodeExtension(o Object, id string) (any, error) {
+	return o.GetExtensions().Decode(id)
+}
+
+func init() {
+	RegisterExtension(OriginAS0, decodeOriginAS0)
+}
+
+// OriginAS0 is the identifier of ARIN's extension listing the origin AS
+// numbers of a network.
+//
+// https://bitbucket.org/nroregistries/rdap-extensions/src/master/arin_originas0.md
+const OriginAS0 = "arin_originas0"
+
+// decodeOriginAS0 decodes the arin_originas0 extension into a []uint32 of
+// origin AS numbers.
+func decodeOriginAS0(members Extensions) (any, error) {
+	raw, ok := members[OriginAS0+"_originautnums"]
+	if !ok {
+		return []uint32(nil), nil
+	}
+
+	var originAutnums []uint32
+	if err := json.Unmarshal(raw, &originAutnums); err != nil {
+		return nil, err
+	}
+	return originAutnums, nil
+}
+
+// OriginAutnums returns the origin AS numbers of the network from the
+// arin_originas0 extension, or nil if the server does not provide them. It
+// returns an error if the decoder registered for the extension has been
+// replaced by one which does not return a []uint32.
+func (n *IPNetwork) OriginAutnums() ([]uint32, error) {
+	data, err := n.Extensions.Decode(OriginAS0)
+	if err != nil {
+		return nil, err
+	}
+	originAutnums, ok := data.([]uint32)
+	if !ok {
+		return nil, fmt.Errorf("extension %s decoded to %T, expected []uint32", OriginAS0, data)
+	}
+	return originAutnums, nil
+}
+
+var knownMembersCache sync.Map
+
+// knownMembers returns the lower case JSON names of the fields of struct
+// type t, including the fields of embedded structs.
+func knownMembers(t reflect.Type) map[string]bool {
+	if known, ok := knownMembersCache.Load(t); ok {
+		return known.(map[string]bool)
+	}
+
+	known := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for name := range knownMembers(field.Type) {
+				known[name] = true
+			}
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		known[strings.ToLower(name)] = true
+	}
+
+	knownMembersCache.Store(t, known)
+	return known
+}
+
+// unmarshalObject unmarshals data into v, a pointer to an alias of an object
+// type, and returns the members of data which are not fields of t, the
+// object type, or one of extra.
+func unmarshalObject(data []byte, v any, t reflect.Type, extra ...string) (Extensions, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	known := knownMembers(t)
+	var extensions Extensions
+	for name, value := range members {
+		if known[strings.ToLower(name)] || containsFold(extra, name) {
+			continue
+		}
+		var compact bytes.Buffer
+		if err := json.Compact(&compact, value); err != nil {
+			return nil, err
+		}
+		if extensions == nil {
+			extensions = make(Extensions)
+		}
+		extensions[name] = compact.Bytes()
+	}
+	return extensions, nil
+}
+
+// marshalObject marshals v, an alias of an object type, and appends the
+// extension members sorted by name. Extension members with the name of a
+// member v already encodes are skipped, so no member is written twice.
+func marshalObject(v any, extensions Extensions) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extensions) == 0 {
+		return data, err
+	}
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	encoded := make(map[string]bool, len(members))
+	for name := range members {
+		encoded[strings.ToLower(name)] = true
+	}
+
+	names := make([]string, 0, len(extensions))
+	for name := range extensions {
+		if !encoded[strings.ToLower(name)] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for i, name := range names {
+		if i > 0 || len(encoded) > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		value := extensions[name]
+		if len(value) == 0 {
+			value = json.RawMessage("null")
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func containsFold(values []string, s string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package openrdap
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOriginAutnums(t *testing.T) {
+	fileData, err := os.ReadFile("test/example_ip_8888.json")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var network IPNetwork
+	if err := json.Unmarshal(fileData, &network); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	originAutnums, err := network.OriginAutnums()
+	if err != nil || len(originAutnums) != 0 {
+		t.Errorf("unexpected origin autnums %v, %v", originAutnums, err)
+	}
+
+	// cidr0 has a field, it is not an extension member
+	if _, ok := network.Extensions["cidr0_cidrs"]; ok {
+		t.Error("expected cidr0_cidrs to be decoded into CIDRs")
+	}
+
+	network = IPNetwork{}
+	if err := json.Unmarshal([]byte(`{"objectClassName": "ip network", "arin_originas0_originautnums": [15169, 36040]}`), &network); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	originAutnums, err = network.OriginAutnums()
+	if err != nil || !cmp.Equal(originAutnums, []uint32{15169, 36040}) {
+		t.Errorf("unexpected origin autnums %v, %v", originAutnums, err)
+	}
+}
+
+func TestRegisterExtension(t *testing.T) {
+	type example struct {
+		Level int `json:"example_level"`
+	}
+	RegisterExtension("example", func(members Extensions) (any, error) {
+		var result example
+		data, err := json.Marshal(members)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &result)
+		return result, err
+	})
+
+	input := `{"objectClassName":"domain","ldhName":"example.com","example_level":3,"other_flag":{"a":true}}`
+	obj, err := Decode([]byte(input))
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	data, err := DecodeExtension(obj, "example")
+	if err != nil || data.(example).Level != 3 {
+		t.Errorf("unexpected extension data %+v, %v", data, err)
+	}
+
+	if _, err := DecodeExtension(obj, "other"); !errors.Is(err, ErrUnknownExtension) {
+		t.Errorf("Expected ErrUnknownExtension, got %v", err)
+	}
+	if members := obj.GetExtensions().Members("other"); string(members["other_flag"]) != `{"a":true}` {
+		t.Errorf("expected the unregistered member to be kept raw, got %v", members)
+	}
+
+	// extension members are written back
+	out, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	for _, member := range []string{`"example_level":3`, `"other_flag":{"a":true}`} {
+		if !strings.Contains(string(out), member) {
+			t.Errorf("expected %s in %s", member, out)
+		}
+	}
+}
+
+func TestMarshalExtensionsWithoutDuplicates(t *testing.T) {
+	domain := &Domain{
+		ObjectClassName: "domain",
+		LDHName:         "example.com",
+		Extensions: Extensions{
+			"ldhName":       json.RawMessage(`"stale.example"`),
+			"example_level": json.RawMessage(`3`),
+		},
+	}
+
+	out, err := json.Marshal(domain)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if n := strings.Count(string(out), `"ldhName"`); n != 1 {
+		t.Errorf("expected ldhName once, got %d times in %s", n, out)
+	}
+	if !strings.Contains(string(out), `"example_level":3`) {
+		t.Errorf("expected the extension member in %s", out)
+	}
+}
+
+func TestOriginAutnumsReplacedDecoder(t *testing.T) {
+	defer RegisterExtension(OriginAS0, decodeOriginAS0)
+	RegisterExtension(OriginAS0, func(members Extensions) (any, error) {
+		return []string{"AS15169"}, nil
+	})
+
+	network := IPNetwork{Extensions: Extensions{"arin_originas0_originautnums": json.RawMessage(`[15169]`)}}
+	if _, err := network.OriginAutnums(); err == nil {
+		t.Error("expected an error for a decoder of another type")
+	}
+}
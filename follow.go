@@ -0,0 +1,240 @@
+package openrdap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+)
+
+// DefaultHydrateConcurrency is the number of concurrent requests used to
+// hydrate a response if no other limit is given.
+const DefaultHydrateConcurrency = 4
+
+// MaxHydrateDepth is the number of levels of stubs Hydrate replaces: the
+// stubs of the object, those of the fetched objects, and so on. It bounds
+// the requests made for registries whose entities refer to each other.
+const MaxHydrateDepth = 3
+
+// ErrUnsupportedMediaType is returned by Follow for links to resources other
+// than RDAP JSON, e.g. HTML pages.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// WithHydration enables hydration of every response returned by the Client,
+// see Hydrate. At most concurrency requests are made at the same time, or
+// DefaultHydrateConcurrency if concurrency is less than 1. Hydration is best
+// effort: stubs which cannot be fetched are left as they are.
+func WithHydration(concurrency int) Option {
+	return func(c *Client) {
+		if concurrency < 1 {
+			concurrency = DefaultHydrateConcurrency
+		}
+		c.hydrateConcurrency = concurrency
+	}
+}
+
+// Follow fetches the target of link, e.g. a "self", "related", "up" or
+// "down" link, and decodes it with Decode. Links with a media type other
+// than RDAP JSON return ErrUnsupportedMediaType. A relative href is resolved
+// against the context URI in link.Value.
+func (c *Client) Follow(ctx context.Context, link Link) (Object, error) {
+	obj, err := c.follow(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	c.autoHydrate(ctx, obj)
+	return obj, nil
+}
+
+func (c *Client) follow(ctx context.Context, link Link) (Object, error) {
+	if !isRDAPMediaType(link.Type) {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMediaType, link.Type)
+	}
+
+	target, err := linkTarget(link)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(body)
+}
+
+// linkTarget returns the absolute URL of the target of link.
+func linkTarget(link Link) (*url.URL, error) {
+	if link.Href == "" {
+		return nil, fmt.Errorf("link %q has no href", link.Rel)
+	}
+
+	target, err := url.Parse(link.Href)
+	if err != nil {
+		return nil, fmt.Errorf("invalid link href %s: %w", link.Href, err)
+	}
+	if !target.IsAbs() {
+		base, err := url.Parse(link.Value)
+		if err != nil || !base.IsAbs() {
+			return nil, fmt.Errorf("relative link href %s without context URI", link.Href)
+		}
+		target = base.ResolveReference(target)
+	}
+	return target, nil
+}
+
+// isRDAPMediaType reports whether a link type refers to RDAP JSON. Links
+// without a type are assumed to.
+func isRDAPMediaType(mediaType string) bool {
+	if mediaType == "" {
+		return true
+	}
+	parsed, _, err := mime.ParseMediaType(mediaType)
+	if err != nil {
+		return false
+	}
+	return parsed == "application/rdap+json" || parsed == "application/json"
+}
+
+// Hydrate replaces the stub nameservers and entities of obj with the objects
+// their self links point to. Registries often embed nameservers with only an
+// ldhName, and entities with only a handle and roles. Entities keep the roles
+// they have in obj, which are specific to the relationship, and the nested
+// entities of the stub if the fetched entity has none.
+//
+// The fetched objects are hydrated in turn, down to MaxHydrateDepth levels.
+// Stubs are fetched concurrently, errors are joined and returned once all
+// requests have completed. Stubs which could not be fetched are unchanged.
+func (c *Client) Hydrate(ctx context.Context, obj Object) error {
+	concurrency := c.hydrateConcurrency
+	if concurrency < 1 {
+		concurrency = DefaultHydrateConcurrency
+	}
+	return c.hydrate(ctx, obj, MaxHydrateDepth, make(chan struct{}, concurrency))
+}
+
+// hydrate replaces the stubs of obj, then hydrates the replaced stubs down to
+// depth levels. The semaphore sem is shared by all levels, and is only held
+// while a request is made, so nested levels cannot starve their parents.
+func (c *Client) hydrate(ctx context.Context, obj Object, depth int, sem chan struct{}) error {
+	if isNilObject(obj) || depth < 1 {
+		return nil
+	}
+
+	// apply replaces target, the stub, with the fetched object
+	type stub struct {
+		link   Link
+		target Object
+		apply  func(Object) error
+	}
+	var stubs []stub
+
+	if domain, ok := obj.(*Domain); ok {
+		for i := range domain.Nameservers {
+			ns := &domain.Nameservers[i]
+			self := LinkByRel(ns, "self")
+			if ns.IPAddresses != nil || self == nil {
+				continue
+			}
+			stubs = append(stubs, stub{*self, ns, func(fetched Object) error {
+				full, ok := fetched.(*Nameserver)
+				if !ok {
+					return fmt.Errorf("%s: expected a nameserver, got %T", self.Href, fetched)
+				}
+				*ns = *full
+				return nil
+			}})
+		}
+	}
+
+	WalkEntities(obj, func(path EntityPath) error {
+		entity := path.Entity()
+		self := LinkByRel(entity, "self")
+		if (len(entity.VCards) > 0 && !entity.VCards[0].isZero()) || self == nil {
+			return nil
+		}
+		stubs = append(stubs, stub{*self, entity, func(fetched Object) error {
+			full, ok := fetched.(*Entity)
+			if !ok {
+				return fmt.Errorf("%s: expected an entity, got %T", self.Href, fetched)
+			}
+			roles, entities := entity.Roles, entity.Entities
+			*entity = *full
+			if len(roles) > 0 {
+				entity.Roles = roles
+			}
+			if len(entity.Entities) == 0 {
+				entity.Entities = entities
+			}
+			return nil
+		}})
+		// the nested entities are hydrated with the fetched entity
+		return SkipChildren
+	})
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	addErr := func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+	for _, s := range stubs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				addErr(ctx.Err())
+				return
+			}
+			fetched, err := c.follow(ctx, s.link)
+			<-sem
+			if err == nil {
+				err = s.apply(fetched)
+			}
+			if err != nil {
+				addErr(err)
+			}
+
+			// the stub is kept if it could not be fetched, its nested
+			// entities may still be hydrated
+			if err := c.hydrate(ctx, s.target, depth-1, sem); err != nil {
+				addErr(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// autoHydrate hydrates obj if hydration is enabled for the Client.
+func (c *Client) autoHydrate(ctx context.Context, obj Object) {
+	if c.hydrateConcurrency > 0 {
+		c.Hydrate(ctx, obj)
+	}
+}
+
+// isNilObject reports whether obj is nil or a nil pointer, e.g. a *Domain
+// decoded from a JSON null.
+func isNilObject(obj Object) bool {
+	if obj == nil {
+		return true
+	}
+	v := reflect.ValueOf(obj)
+	return v.Kind() == reflect.Pointer && v.IsNil()
+}
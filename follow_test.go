@@ -0,0 +1,179 @@
+package openrdap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newHydrationServer(t *testing.T, inFlight, maxInFlight *int32) *httptest.Server {
+	t.Helper()
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n := atomic.AddInt32(inFlight, 1); n > atomic.LoadInt32(maxInFlight) {
+			atomic.StoreInt32(maxInFlight, n)
+		}
+		defer atomic.AddInt32(inFlight, -1)
+		time.Sleep(10 * time.Millisecond)
+
+		base := server.URL
+		var body string
+		switch r.URL.Path {
+		case "/domain/example.com":
+			body = `{
+  "objectClassName": "domain",
+  "ldhName": "example.com",
+  "nameservers": [
+    {"objectClassName": "nameserver", "ldhName": "ns1.example.com",
+     "links": [{"rel": "self", "href": "` + base + `/nameserver/ns1.example.com", "type": "application/rdap+json"}]},
+    {"objectClassName": "nameserver", "ldhName": "ns2.example.com",
+     "links": [{"rel": "self", "href": "` + base + `/nameserver/ns2.example.com"}]}
+  ],
+  "entities": [
+    {"objectClassName": "entity", "handle": "REG-1", "roles": ["registrar"],
+     "links": [{"rel": "self", "href": "/entity/REG-1", "value": "` + base + `/domain/example.com"}]}
+  ]
+}`
+		case "/nameserver/ns1.example.com":
+			body = `{"objectClassName": "nameserver", "ldhName": "ns1.example.com", "ipAddresses": {"v4": ["192.0.2.1"]}}`
+		case "/nameserver/ns2.example.com":
+			body = `{"objectClassName": "nameserver", "ldhName": "ns2.example.com", "ipAddresses": {"v6": ["2001:db8::2"]}}`
+		case "/entity/REG-1":
+			body = `{"objectClassName": "entity", "handle": "REG-1",
+  "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar"]]],
+  "entities": [
+    {"objectClassName": "entity", "handle": "ABUSE-1", "roles": ["abuse"],
+     "links": [{"rel": "self", "href": "` + base + `/entity/ABUSE-1"}]}
+  ]}`
+		case "/entity/ABUSE-1":
+			body = `{"objectClassName": "entity", "handle": "ABUSE-1",
+  "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["email", {}, "text", "abuse@example.net"]]]}`
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			body = `{"errorCode": 404, "title": "Not Found"}`
+		}
+		w.Header().Set("Content-Type", "application/rdap+json")
+		w.Write([]byte(body))
+	}))
+	return server
+}
+
+func TestHydration(t *testing.T) {
+	var inFlight, maxInFlight int32
+	mockServer := newHydrationServer(t, &inFlight, &maxInFlight)
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.Client(), nil, WithHydration(1))
+
+	rdapInfo, err := client.GetRDAPInfoFromServer(context.Background(), mockServer.URL+"/", "example.com", DNS)
+	if err != nil {
+		t.Fatalf("Failed to get RDAP info: %v", err)
+	}
+	domain := rdapInfo.(*Domain)
+
+	if ns := domain.Nameservers[0]; ns.IPAddresses == nil || ns.IPAddresses.V4[0] != "192.0.2.1" {
+		t.Errorf("expected ns1 to be hydrated, got %+v", ns)
+	}
+	if ns := domain.Nameservers[1]; ns.IPAddresses == nil || ns.IPAddresses.V6[0] != "2001:db8::2" {
+		t.Errorf("expected ns2 to be hydrated, got %+v", ns)
+	}
+
+	registrar := domain.GetEntityFromRole("registrar")
+	if registrar == nil || registrar.VCards[0].FullName != "Example Registrar" {
+		t.Errorf("expected the registrar to be hydrated with its roles kept, got %+v", registrar)
+	}
+
+	if maxInFlight > 2 {
+		// one request for the domain, at most one for hydration
+		t.Errorf("expected at most 2 requests at a time, got %d", maxInFlight)
+	}
+}
+
+func TestHydrateNested(t *testing.T) {
+	var inFlight, maxInFlight int32
+	mockServer := newHydrationServer(t, &inFlight, &maxInFlight)
+	defer mockServer.Close()
+
+	// a stub registrar, whose fetched entity has a stub abuse contact, and a
+	// stub tech contact nested in an entity without a self link
+	domain := &Domain{
+		Entities: []Entity{
+			{Handle: "REG-1", Roles: []string{"registrar"}, Links: []Link{{Rel: "self", Href: mockServer.URL + "/entity/REG-1"}}},
+			{Handle: "R-1", Roles: []string{"registrant"}, Entities: []Entity{
+				{Handle: "ABUSE-1", Roles: []string{"technical"}, Links: []Link{{Rel: "self", Href: mockServer.URL + "/entity/ABUSE-1"}}},
+			}},
+		},
+	}
+
+	client := NewClient(mockServer.Client(), nil)
+	if err := client.Hydrate(context.Background(), domain); err != nil {
+		t.Fatalf("Failed to hydrate: %v", err)
+	}
+
+	abuse := EntityByRole(domain, "abuse")
+	if abuse == nil || abuse.VCard().Email != "abuse@example.net" {
+		t.Errorf("expected the abuse contact of the fetched registrar to be hydrated, got %+v", abuse)
+	}
+	if tech := EntityByRole(domain, "technical"); tech == nil || tech.VCard().Email != "abuse@example.net" {
+		t.Errorf("expected the nested tech contact to be hydrated, got %+v", tech)
+	}
+}
+
+func TestHydrateDisabled(t *testing.T) {
+	var inFlight, maxInFlight int32
+	mockServer := newHydrationServer(t, &inFlight, &maxInFlight)
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.Client(), nil)
+	rdapInfo, err := client.GetRDAPInfoFromServer(context.Background(), mockServer.URL+"/", "example.com", DNS)
+	if err != nil {
+		t.Fatalf("Failed to get RDAP info: %v", err)
+	}
+	domain := rdapInfo.(*Domain)
+	if domain.Nameservers[0].IPAddresses != nil {
+		t.Error("expected no hydration without WithHydration")
+	}
+
+	// explicit hydration reports stubs which cannot be fetched
+	domain.Nameservers[1].Links[0].Href = mockServer.URL + "/nameserver/missing"
+	err = client.Hydrate(context.Background(), domain)
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) {
+		t.Errorf("expected an error response, got %v", err)
+	}
+	if domain.Nameservers[0].IPAddresses == nil || domain.Nameservers[1].IPAddresses != nil {
+		t.Errorf("unexpected nameservers %+v", domain.Nameservers)
+	}
+}
+
+func TestFollow(t *testing.T) {
+	var inFlight, maxInFlight int32
+	mockServer := newHydrationServer(t, &inFlight, &maxInFlight)
+	defer mockServer.Close()
+
+	client := NewClient(mockServer.Client(), nil)
+	ctx := context.Background()
+
+	obj, err := client.Follow(ctx, Link{Rel: "related", Href: mockServer.URL + "/nameserver/ns1.example.com", Type: "application/rdap+json"})
+	if err != nil {
+		t.Fatalf("Failed to follow link: %v", err)
+	}
+	if ns, ok := obj.(*Nameserver); !ok || ns.LDHName != "ns1.example.com" {
+		t.Errorf("Expected nameserver ns1.example.com, got %+v", obj)
+	}
+
+	_, err = client.Follow(ctx, Link{Rel: "alternate", Href: "https://example.com/whois.html", Type: "text/html"})
+	if !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Errorf("Expected ErrUnsupportedMediaType, got %v", err)
+	}
+
+	if _, err = client.Follow(ctx, Link{Rel: "up", Href: "/ip/192.0.2.0/24"}); err == nil || !strings.Contains(err.Error(), "context URI") {
+		t.Errorf("Expected an error for a relative link without context, got %v", err)
+	}
+}
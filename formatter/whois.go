@@ -0,0 +1,464 @@
+// Package formatter writes RDAP objects in the key/value layout of WHOIS, so
+// tools parsing WHOIS output keep working on RDAP data.
+//
+// Domains follow the registration data directory services specification of
+// the 2013 ICANN Registrar Accreditation Agreement, e.g.
+//
+//	Domain Name: EXAMPLE.COM
+//	Registry Domain ID: 2336799_DOMAIN_COM-VRSN
+//	Registrar WHOIS Server: whois.example-registrar.com
+//	...
+//
+// IP networks and autonomous system numbers use the keys of the RIR WHOIS
+// services (NetRange, CIDR, ASNumber, ...). Contacts are written for every
+// entity, with keys named after its roles, e.g. "Registrant Email" or
+// "Registrant Abuse Phone" for an abuse contact nested in a registrant.
+package formatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/perihwk/openrdap"
+)
+
+// ErrUnsupportedObject is returned by WriteWHOIS for objects other than the
+// RDAP object classes and search results.
+var ErrUnsupportedObject = errors.New("unsupported object")
+
+// rolePrefixes are the key prefixes of the contacts with the given role.
+var rolePrefixes = map[string]string{
+	"registrant":     "Registrant",
+	"administrative": "Admin",
+	"technical":      "Tech",
+	"billing":        "Billing",
+	"abuse":          "Abuse",
+	"registrar":      "Registrar",
+	"reseller":       "Reseller",
+	"sponsor":        "Sponsor",
+	"proxy":          "Proxy",
+	"notifications":  "Notifications",
+	"noc":            "NOC",
+}
+
+// WriteWHOIS writes obj to w in the WHOIS layout. obj is a *openrdap.Domain,
+// *openrdap.IPNetwork, *openrdap.Autnum, *openrdap.Nameserver,
+// *openrdap.Entity or *openrdap.SearchResults, whose objects are separated
+// by blank lines.
+func WriteWHOIS(w io.Writer, obj openrdap.Object) error {
+	f := &whois{}
+	if err := f.object(obj); err != nil {
+		return err
+	}
+	_, err := w.Write(f.buf.Bytes())
+	return err
+}
+
+type whois struct {
+	buf bytes.Buffer
+}
+
+// field writes a "key: value" line. Keys are written with an empty value if
+// the value is missing, as required for WHOIS.
+func (f *whois) field(key, value string) {
+	if value == "" {
+		fmt.Fprintf(&f.buf, "%s:\n", key)
+		return
+	}
+	fmt.Fprintf(&f.buf, "%s: %s\n", key, value)
+}
+
+// fields writes a line for each value, or one with an empty value if there
+// are none.
+func (f *whois) fields(key string, values []string) {
+	if len(values) == 0 {
+		f.field(key, "")
+	}
+	for _, value := range values {
+		f.field(key, value)
+	}
+}
+
+// optional writes a "key: value" line if value is not empty.
+func (f *whois) optional(key, value string) {
+	if value != "" {
+		f.field(key, value)
+	}
+}
+
+func (f *whois) object(obj openrdap.Object) error {
+	switch obj := obj.(type) {
+	case *openrdap.Domain:
+		f.domain(obj)
+	case *openrdap.IPNetwork:
+		f.ipNetwork(obj)
+	case *openrdap.Autnum:
+		f.autnum(obj)
+	case *openrdap.Nameserver:
+		f.nameserver(obj)
+	case *openrdap.Entity:
+		f.entity(obj)
+	case *openrdap.SearchResults:
+		for i, result := range obj.Objects() {
+			if i > 0 {
+				f.buf.WriteString("\n")
+			}
+			if err := f.object(result); err != nil {
+				return err
+			}
+		}
+		f.notices(obj.Notices)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedObject, obj)
+	}
+	return nil
+}
+
+func (f *whois) domain(d *openrdap.Domain) {
+	registrar := openrdap.EntityByRole(d, "registrar")
+	var registrarVCard openrdap.VCard
+	var registrarWhois, ianaID string
+	var abuse *openrdap.Entity
+	if registrar != nil {
+		registrarVCard = registrar.VCard()
+		registrarWhois = registrar.Port43
+		ianaID = publicID(registrar, "IANA Registrar ID")
+		for i := range registrar.Entities {
+			if registrar.Entities[i].HasRole("abuse") {
+				abuse = &registrar.Entities[i]
+				break
+			}
+		}
+	}
+	if d.Port43 != "" {
+		registrarWhois = d.Port43
+	}
+	var abuseVCard openrdap.VCard
+	if abuse != nil {
+		abuseVCard = abuse.VCard()
+	}
+
+	name := d.LDHName
+	if name == "" {
+		name = d.UnicodeName
+	}
+	f.field("Domain Name", name)
+	f.field("Registry Domain ID", d.Handle)
+	f.field("Registrar WHOIS Server", registrarWhois)
+	f.field("Registrar URL", firstValue(registrarVCard.URLs))
+	f.field("Updated Date", eventDate(d, openrdap.EventLastChanged))
+	f.field("Creation Date", eventDate(d, openrdap.EventRegistration))
+	f.field("Registry Expiry Date", eventDate(d, openrdap.EventExpiration))
+	f.optional("Registrar Registration Expiration Date", eventDate(d, openrdap.EventRegistrarExpiration))
+	f.field("Registrar", registrarVCard.FullName)
+	f.field("Registrar IANA ID", ianaID)
+	f.field("Registrar Abuse Contact Email", abuseVCard.Email)
+	f.field("Registrar Abuse Contact Phone", phoneNumber(abuseVCard.Voice()))
+	f.fields("Domain Status", statuses(d.Status, true))
+
+	// the registrar and its abuse contact are written above
+	openrdap.WalkEntities(d, func(path openrdap.EntityPath) error {
+		if e := path.Entity(); e == registrar || e == abuse {
+			return nil
+		}
+		f.contacts(path)
+		return nil
+	})
+
+	var nameservers []string
+	for _, ns := range d.Nameservers {
+		nameservers = append(nameservers, ns.LDHName)
+	}
+	f.fields("Name Server", nameservers)
+	f.dnssec(d.SecureDNS)
+
+	for _, notice := range d.Notices {
+		if strings.EqualFold(notice.Title, "RDDS Inaccuracy Complaint Form") && len(notice.Links) > 0 {
+			f.field("URL of the ICANN Whois Inaccuracy Complaint Form", notice.Links[0].Href)
+		}
+	}
+	if date := eventDate(d, openrdap.EventLastUpdateOfRDAPDatabase); date != "" {
+		fmt.Fprintf(&f.buf, ">>> Last update of WHOIS database: %s <<<\n", date)
+	}
+	f.notices(d.Notices)
+}
+
+// dnssec writes the delegation state and the DS and key records.
+func (f *whois) dnssec(secureDNS *openrdap.SecureDNS) {
+	if secureDNS == nil || !secureDNS.DelegationSigned {
+		f.field("DNSSEC", "unsigned")
+		return
+	}
+	f.field("DNSSEC", "signedDelegation")
+	for _, ds := range secureDNS.DS {
+		f.field("DNSSEC DS Data", fmt.Sprintf("%d %d %d %s", ds.KeyTag, ds.Algorithm, ds.DigestType, ds.Digest))
+	}
+	for _, key := range secureDNS.Keys {
+		f.field("DNSSEC Key Data", fmt.Sprintf("%d %d %d %s", key.Flags, key.Protocol, key.Algorithm, key.PublicKey))
+	}
+}
+
+func (f *whois) ipNetwork(n *openrdap.IPNetwork) {
+	var cidrs []string
+	for _, prefix := range n.Prefixes() {
+		cidrs = append(cidrs, prefix.String())
+	}
+	netRange := ""
+	if start, end := n.StartAddr(), n.EndAddr(); start.IsValid() && end.IsValid() {
+		netRange = start.String() + " - " + end.String()
+	}
+
+	f.field("NetRange", netRange)
+	f.field("CIDR", strings.Join(cidrs, ", "))
+	f.field("NetName", n.Name)
+	f.field("NetHandle", n.Handle)
+	f.field("Parent", n.ParentHandle)
+	f.field("NetType", n.Type)
+	if asns, err := n.OriginAutnums(); err == nil && len(asns) > 0 {
+		var origins []string
+		for _, asn := range asns {
+			origins = append(origins, "AS"+strconv.FormatUint(uint64(asn), 10))
+		}
+		f.field("OriginAS", strings.Join(origins, ", "))
+	}
+	f.optional("Country", n.Country)
+	f.fields("Status", statuses(n.Status, false))
+	f.registration(n)
+	f.entities(n)
+	f.notices(n.Notices)
+}
+
+func (f *whois) autnum(a *openrdap.Autnum) {
+	asNumber := strconv.FormatUint(uint64(a.StartAutnum), 10)
+	if a.EndAutnum > a.StartAutnum {
+		asNumber += " - " + strconv.FormatUint(uint64(a.EndAutnum), 10)
+	}
+
+	f.field("ASNumber", asNumber)
+	f.field("ASName", a.Name)
+	f.field("ASHandle", a.Handle)
+	f.optional("ASType", a.Type)
+	f.optional("Country", a.Country)
+	f.fields("Status", statuses(a.Status, false))
+	f.registration(a)
+	f.entities(a)
+	f.notices(a.Notices)
+}
+
+func (f *whois) nameserver(n *openrdap.Nameserver) {
+	f.field("Server Name", n.LDHName)
+	f.optional("Registry Server ID", n.Handle)
+
+	var addrs []string
+	if n.IPAddresses != nil {
+		addrs = append(addrs, n.IPAddresses.V4...)
+		addrs = append(addrs, n.IPAddresses.V6...)
+	}
+	for _, addr := range addrs {
+		f.field("IP Address", addr)
+	}
+	f.fields("Server Status", statuses(n.Status, false))
+	f.registration(n)
+	f.entities(n)
+	f.notices(n.Notices)
+}
+
+func (f *whois) entity(e *openrdap.Entity) {
+	f.field("Handle", e.Handle)
+	f.field("Roles", strings.Join(e.Roles, ", "))
+	f.contact("", e)
+	f.fields("Status", statuses(e.Status, false))
+	f.registration(e)
+	f.entities(e)
+	f.notices(e.Notices)
+}
+
+// registration writes the registration and last changed dates, the self link
+// and the remarks of a network, autnum, nameserver or entity.
+func (f *whois) registration(obj openrdap.Object) {
+	f.optional("RegDate", eventDate(obj, openrdap.EventRegistration))
+	f.optional("Updated", eventDate(obj, openrdap.EventLastChanged))
+	if link := openrdap.LinkByRel(obj, "self"); link != nil {
+		f.field("Ref", link.Href)
+	}
+	for _, remark := range obj.GetRemarks() {
+		for _, line := range remark.Description {
+			f.field("Comment", line)
+		}
+	}
+}
+
+// entities writes the contacts of all entities of obj.
+func (f *whois) entities(obj openrdap.Object) {
+	openrdap.WalkEntities(obj, func(path openrdap.EntityPath) error {
+		f.contacts(path)
+		return nil
+	})
+}
+
+// contacts writes the contact of the entity path leads to once for each of
+// its roles. Keys are prefixed with the roles along the path, e.g.
+// "Registrant Abuse".
+func (f *whois) contacts(path openrdap.EntityPath) {
+	var parent []string
+	for _, e := range path[:len(path)-1] {
+		parent = append(parent, rolePrefix(e.Roles))
+	}
+
+	e := path.Entity()
+	roles := e.Roles
+	if len(roles) == 0 {
+		roles = []string{""}
+	}
+	for _, role := range roles {
+		prefix := strings.Join(append(parent, rolePrefix([]string{role})), " ")
+		f.contact(prefix, e)
+	}
+}
+
+// contact writes the handle, name, organization, address, telephone, fax and
+// email of e with keys prefixed by prefix.
+func (f *whois) contact(prefix string, e *openrdap.Entity) {
+	key := func(name string) string {
+		if prefix == "" {
+			return name
+		}
+		return prefix + " " + name
+	}
+	v := e.VCard()
+
+	if prefix != "" {
+		f.field("Registry "+prefix+" ID", e.Handle)
+	}
+	f.field(key("Name"), v.FullName)
+	f.field(key("Organization"), v.Org)
+	f.fields(key("Street"), street(v.Address))
+	f.field(key("City"), v.Address.Locality)
+	f.field(key("State/Province"), v.Address.Region)
+	f.field(key("Postal Code"), v.Address.PostalCode)
+	f.field(key("Country"), v.Address.Country)
+
+	voice, voiceExt := splitPhone(v.Voice())
+	f.field(key("Phone"), voice)
+	f.optional(key("Phone Ext"), voiceExt)
+	fax, faxExt := splitPhone(v.Fax())
+	f.field(key("Fax"), fax)
+	f.optional(key("Fax Ext"), faxExt)
+	f.field(key("Email"), v.Email)
+}
+
+// notices writes the notices after a blank line, the title followed by the
+// description and the link targets.
+func (f *whois) notices(notices []openrdap.Notice) {
+	for _, notice := range notices {
+		f.buf.WriteString("\n")
+		if notice.Title != "" {
+			f.buf.WriteString(notice.Title + "\n")
+		}
+		for _, line := range notice.Description {
+			f.buf.WriteString(line + "\n")
+		}
+		for _, link := range notice.Links {
+			f.buf.WriteString(link.Href + "\n")
+		}
+	}
+}
+
+// rolePrefix returns the key prefix for the first of roles, "Contact" for
+// entities without roles.
+func rolePrefix(roles []string) string {
+	if len(roles) == 0 || roles[0] == "" {
+		return "Contact"
+	}
+	role := strings.ToLower(roles[0])
+	if prefix, ok := rolePrefixes[role]; ok {
+		return prefix
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
+
+// publicID returns the identifier of the public ID of e with the given type.
+func publicID(e *openrdap.Entity, typ string) string {
+	for _, id := range e.PublicIDs {
+		if strings.EqualFold(id.Type, typ) {
+			return id.Identifier
+		}
+	}
+	return ""
+}
+
+// eventDate returns the date of the event of obj with the given action, in
+// the format of WHOIS, e.g. "2024-01-05T15:38:38Z", or "". Actions are
+// compared case-insensitively.
+func eventDate(obj openrdap.Object, action string) string {
+	event := openrdap.EventByAction(obj, action)
+	switch {
+	case event == nil:
+		return ""
+	case event.Time.IsZero():
+		return event.Date
+	default:
+		return event.Time.UTC().Format(time.RFC3339)
+	}
+}
+
+// statuses returns the status values as EPP codes followed by the ICANN URL
+// of the code if epp is set and the status has one, as in
+// "clientHold https://icann.org/epp#clientHold".
+func statuses(values []openrdap.Status, epp bool) []string {
+	var result []string
+	for _, status := range values {
+		if code, ok := status.EPP(); ok && epp {
+			result = append(result, code+" https://icann.org/epp#"+code)
+		} else {
+			result = append(result, string(status))
+		}
+	}
+	return result
+}
+
+// street returns the street lines of addr, or the lines of the label if the
+// address has no structured components.
+func street(addr openrdap.Address) []string {
+	var lines []string
+	for _, part := range []string{addr.PostOfficeBox, addr.ExtendedAddress, addr.StreetAddress} {
+		if part != "" {
+			lines = append(lines, strings.Split(part, "\n")...)
+		}
+	}
+	if len(lines) == 0 && addr.Locality == "" && addr.Country == "" && addr.Label != "" {
+		lines = strings.Split(addr.Label, "\n")
+	}
+	return lines
+}
+
+// splitPhone returns the number and extension of a telephone value, e.g.
+// "+1.5555551212" and "1234" for "tel:+1.5555551212;ext=1234".
+func splitPhone(value string) (string, string) {
+	number := strings.TrimPrefix(value, "tel:")
+	number, params, _ := strings.Cut(number, ";")
+	for _, param := range strings.Split(params, ";") {
+		if ext, ok := strings.CutPrefix(param, "ext="); ok {
+			return number, ext
+		}
+	}
+	return number, ""
+}
+
+// phoneNumber returns the number of a telephone value without extension.
+func phoneNumber(value string) string {
+	number, _ := splitPhone(value)
+	return number
+}
+
+func firstValue(values []openrdap.Value) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0].Value
+}
@@ -0,0 +1,204 @@
+package formatter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/perihwk/openrdap"
+)
+
+func decodeFile(t *testing.T, name string) openrdap.Object {
+	t.Helper()
+
+	fileData, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	obj, err := openrdap.Decode(fileData)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	return obj
+}
+
+func writeWHOIS(t *testing.T, obj openrdap.Object) string {
+	t.Helper()
+
+	var b strings.Builder
+	if err := WriteWHOIS(&b, obj); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	return b.String()
+}
+
+func TestWriteWHOISDomain(t *testing.T) {
+	output := writeWHOIS(t, decodeFile(t, "../test/example_domain_perihwk.json"))
+
+	expected := `Domain Name: PERIHWK.COM
+Registry Domain ID: 2358260608_DOMAIN_COM-VRSN
+Registrar WHOIS Server:
+Registrar URL:
+Updated Date: 2024-01-05T15:38:38Z
+Creation Date: 2019-02-05T03:32:53Z
+Registry Expiry Date: 2025-02-05T03:32:53Z
+Registrar: NameCheap, Inc.
+Registrar IANA ID: 1068
+Registrar Abuse Contact Email: abuse@namecheap.com
+Registrar Abuse Contact Phone: +1.6613102107
+Domain Status: clientTransferProhibited https://icann.org/epp#clientTransferProhibited
+Name Server: PENNY.NS.CLOUDFLARE.COM
+Name Server: TONY.NS.CLOUDFLARE.COM
+DNSSEC: signedDelegation
+DNSSEC DS Data: 2371 13 2 341B3325B93B3DAEC42FBC018D7903FCBF26E52A0638DD61D5E6B3F269DA44C1
+URL of the ICANN Whois Inaccuracy Complaint Form: https://icann.org/wicf
+>>> Last update of WHOIS database: 2024-10-16T17:07:22Z <<<
+`
+	if !strings.HasPrefix(output, expected) {
+		t.Errorf("unexpected output (-want +got):\n%s", cmp.Diff(expected, output))
+	}
+	if !strings.Contains(output, "\nTerms of Use\nService subject to Terms of Use.\n") {
+		t.Errorf("expected notices in output:\n%s", output)
+	}
+}
+
+func TestWriteWHOISIPNetwork(t *testing.T) {
+	output := writeWHOIS(t, decodeFile(t, "../test/example_ip_8888.json"))
+
+	for _, line := range []string{
+		"NetRange: 8.8.8.0 - 8.8.8.255",
+		"CIDR: 8.8.8.0/24",
+		"NetHandle: NET-8-8-8-0-2",
+		"Parent: NET-8-0-0-0-0",
+		"RegDate: 2023-12-28T22:24:33Z",
+		"Registry Registrant ID: GOGL",
+		"Registrant Name: Google LLC",
+		"Registrant Abuse Email: network-abuse@google.com",
+		"Registrant Tech Phone: +1-650-253-0000",
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("expected line %q in output:\n%s", line, output)
+		}
+	}
+}
+
+func TestWriteWHOISContacts(t *testing.T) {
+	var domain openrdap.Domain
+	err := json.Unmarshal([]byte(`{
+  "objectClassName": "domain",
+  "ldhName": "example.com",
+  "entities": [
+    {"objectClassName": "entity", "roles": ["registrar"]},
+    {"objectClassName": "entity", "handle": "C-1", "roles": ["administrative", "technical"],
+     "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Jane Doe"],
+       ["adr", {}, "text", ["", "Suite 100", "123 Example St", "Anytown", "AP", "A1A1A1", "AA"]],
+       ["tel", {"type": "voice"}, "uri", "tel:+1.5555551212;ext=1234"],
+       ["tel", {"type": "fax"}, "uri", "tel:+1.5555551213"]]]}
+  ]
+}`), &domain)
+	if err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	output := writeWHOIS(t, &domain)
+
+	// the registrar has no vCard and no abuse contact
+	for _, line := range []string{
+		"Registrar:",
+		"Registrar Abuse Contact Email:",
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("expected line %q in output:\n%s", line, output)
+		}
+	}
+
+	expected := `Registry Admin ID: C-1
+Admin Name: Jane Doe
+Admin Organization:
+Admin Street: Suite 100
+Admin Street: 123 Example St
+Admin City: Anytown
+Admin State/Province: AP
+Admin Postal Code: A1A1A1
+Admin Country: AA
+Admin Phone: +1.5555551212
+Admin Phone Ext: 1234
+Admin Fax: +1.5555551213
+Admin Email:
+Registry Tech ID: C-1
+`
+	if !strings.Contains(output, expected) {
+		t.Errorf("unexpected output, expected:\n%s\ngot:\n%s", expected, output)
+	}
+}
+
+func TestWriteWHOISEntityWithoutVCard(t *testing.T) {
+	entity := &openrdap.Entity{
+		Handle:   "REG-1",
+		Roles:    []string{"registrar"},
+		Entities: []openrdap.Entity{{Roles: []string{"abuse"}}},
+	}
+
+	output := writeWHOIS(t, entity)
+	for _, line := range []string{"Handle: REG-1", "Roles: registrar", "Name:", "Abuse Email:"} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("expected line %q in output:\n%s", line, output)
+		}
+	}
+}
+
+func TestWriteWHOISSearchResults(t *testing.T) {
+	results := &openrdap.SearchResults{
+		Nameservers: []openrdap.Nameserver{
+			{LDHName: "ns1.example.com", IPAddresses: &openrdap.IPAddressSet{V4: []string{"192.0.2.1"}}},
+			{LDHName: "ns2.example.com"},
+		},
+	}
+
+	expected := `Server Name: ns1.example.com
+IP Address: 192.0.2.1
+Server Status:
+
+Server Name: ns2.example.com
+Server Status:
+`
+	if output := writeWHOIS(t, results); output != expected {
+		t.Errorf("unexpected output (-want +got):\n%s", cmp.Diff(expected, output))
+	}
+}
+
+func TestWriteWHOISEventActionCase(t *testing.T) {
+	var domain openrdap.Domain
+	err := json.Unmarshal([]byte(`{
+  "objectClassName": "domain",
+  "ldhName": "example.com",
+  "events": [
+    {"eventAction": "Registration", "eventDate": "2019-02-05T03:32:53Z"},
+    {"eventAction": "EXPIRATION", "eventDate": "2025-02-05T03:32:53Z"}
+  ]
+}`), &domain)
+	if err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	output := writeWHOIS(t, &domain)
+	for _, line := range []string{
+		"Creation Date: 2019-02-05T03:32:53Z",
+		"Registry Expiry Date: 2025-02-05T03:32:53Z",
+	} {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("expected line %q in output:\n%s", line, output)
+		}
+	}
+}
+
+func TestWriteWHOISUnsupported(t *testing.T) {
+	var b strings.Builder
+	err := WriteWHOIS(&b, &openrdap.RawObject{})
+	if !errors.Is(err, ErrUnsupportedObject) {
+		t.Errorf("expected ErrUnsupportedObject, got %v", err)
+	}
+}
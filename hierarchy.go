@@ -0,0 +1,194 @@
+package openrdap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net/netip"
+)
+
+// maxNetworkChain bounds the length of the chain returned by
+// GetIPNetworkChain.
+const maxNetworkChain = 32
+
+var (
+	// ErrNoLink is returned when an object has no link with the relation
+	// needed, e.g. a network without "down" links.
+	ErrNoLink = errors.New("no link with the relation")
+
+	// ErrNetworkCycle is returned by GetIPNetworkChain when the parent of a
+	// network is a network already in the chain.
+	ErrNetworkCycle = errors.New("cycle in network hierarchy")
+)
+
+// upRels and downRels are the link relations pointing to the parent and
+// child networks: "up" and "down" from RFC 8288, and the "rdap-" variants of
+// the RIR search extension.
+var (
+	upRels   = []string{"up", "rdap-up"}
+	downRels = []string{"down", "rdap-down"}
+)
+
+// GetIPNetworkChain returns the allocation chain of addr, from the most
+// specific network, e.g. a customer reassignment, up to the allocation of the
+// RIR. If a parent cannot be fetched, or is already in the chain
+// (ErrNetworkCycle), the chain up to that point is returned together with the
+// error.
+func (c *Client) GetIPNetworkChain(ctx context.Context, addr netip.Addr) ([]*IPNetwork, error) {
+	network, err := c.GetRDAPFromIPAddr(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := []*IPNetwork{network}
+	seen := map[string]bool{networkKey(network): true}
+	for len(chain) < maxNetworkChain {
+		parent, err := c.GetParentNetwork(ctx, chain[len(chain)-1])
+		if err != nil {
+			return chain, err
+		}
+		if parent == nil {
+			break
+		}
+		key := networkKey(parent)
+		if seen[key] {
+			return chain, fmt.Errorf("%w: %s is above %s", ErrNetworkCycle, key, networkKey(chain[len(chain)-1]))
+		}
+		seen[key] = true
+		chain = append(chain, parent)
+	}
+	return chain, nil
+}
+
+// networkKey identifies a network by its handle, its self link or its range.
+func networkKey(network *IPNetwork) string {
+	if network.Handle != "" {
+		return network.Handle
+	}
+	if self := LinkByRel(network, "self"); self != nil && self.Href != "" {
+		return self.Href
+	}
+	return network.StartAddress + " - " + network.EndAddress
+}
+
+// GetParentNetwork returns the network directly above network, or nil if
+// network is at the top of the hierarchy.
+//
+// The "up" link of network is followed if it has one. Otherwise, if network
+// has a ParentHandle, the smallest prefix strictly containing network is
+// queried, for which the server returns the most specific network covering
+// it, i.e. the parent.
+func (c *Client) GetParentNetwork(ctx context.Context, network *IPNetwork) (*IPNetwork, error) {
+	if link := linkByRels(network, upRels); link != nil {
+		return c.followNetwork(ctx, *link)
+	}
+	if network.ParentHandle == "" {
+		return nil, nil
+	}
+
+	prefix, ok := parentPrefix(network)
+	if !ok {
+		return nil, fmt.Errorf("network %s has no valid range to find parent %s", network.Handle, network.ParentHandle)
+	}
+	parent, err := c.GetRDAPFromIPPrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// the server found nothing above network
+	if parent.Handle == network.Handle {
+		return nil, nil
+	}
+	return parent, nil
+}
+
+// GetChildNetworks returns the networks directly below network, following
+// its "down" links. Servers which do not expose child networks return
+// ErrNoLink.
+func (c *Client) GetChildNetworks(ctx context.Context, network *IPNetwork) ([]*IPNetwork, error) {
+	link := linkByRels(network, downRels)
+	if link == nil {
+		return nil, fmt.Errorf("%w: down", ErrNoLink)
+	}
+
+	obj, err := c.Follow(ctx, *link)
+	if err != nil {
+		return nil, err
+	}
+
+	switch obj := obj.(type) {
+	case *IPNetwork:
+		return []*IPNetwork{obj}, nil
+	case *SearchResults:
+		children := make([]*IPNetwork, len(obj.IPNetworks))
+		for i := range obj.IPNetworks {
+			children[i] = &obj.IPNetworks[i]
+		}
+		return children, nil
+	}
+	return nil, fmt.Errorf("%s: expected ip networks, got %T", link.Href, obj)
+}
+
+func (c *Client) followNetwork(ctx context.Context, link Link) (*IPNetwork, error) {
+	obj, err := c.Follow(ctx, link)
+	if err != nil {
+		return nil, err
+	}
+	network, ok := obj.(*IPNetwork)
+	if !ok {
+		return nil, fmt.Errorf("%s: expected an ip network, got %T", link.Href, obj)
+	}
+	return network, nil
+}
+
+// linkByRels returns the first link of o with any of rels.
+func linkByRels(o Object, rels []string) *Link {
+	for _, rel := range rels {
+		if link := LinkByRel(o, rel); link != nil {
+			return link
+		}
+	}
+	return nil
+}
+
+// parentPrefix returns the smallest prefix strictly containing the range of
+// network.
+func parentPrefix(network *IPNetwork) (netip.Prefix, bool) {
+	start, end, ok := network.addrRange()
+	if !ok {
+		return netip.Prefix{}, false
+	}
+
+	prefix := netip.PrefixFrom(start, commonPrefixLen(start, end)).Masked()
+	if prefix.Addr() == start && lastAddr(prefix) == end {
+		if prefix.Bits() == 0 {
+			return netip.Prefix{}, false
+		}
+		prefix = netip.PrefixFrom(start, prefix.Bits()-1).Masked()
+	}
+	return prefix, true
+}
+
+// commonPrefixLen returns the number of leading bits a and b have in common.
+func commonPrefixLen(a, b netip.Addr) int {
+	ab, bb := a.AsSlice(), b.AsSlice()
+	n := 0
+	for i := range ab {
+		if x := ab[i] ^ bb[i]; x != 0 {
+			return n + bits.LeadingZeros8(x)
+		}
+		n += 8
+	}
+	return n
+}
+
+// lastAddr returns the last address of prefix.
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	b := prefix.Masked().Addr().AsSlice()
+	for i := prefix.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 0x80 >> (i % 8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
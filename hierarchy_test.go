@@ -0,0 +1,146 @@
+package openrdap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/perihwk/openrdap/bootstrap"
+)
+
+func TestGetIPNetworkChain(t *testing.T) {
+	fileData, err := os.ReadFile("test/example_ip_8888.json")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var requestPaths []string
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestPaths = append(requestPaths, r.URL.RequestURI())
+		w.Header().Set("Content-Type", "application/rdap+json")
+
+		switch r.URL.RequestURI() {
+		case "/rdap/ip/8.8.8.8":
+			w.Write([]byte(`{"objectClassName": "ip network", "handle": "NET-CUST",
+  "startAddress": "8.8.8.0", "endAddress": "8.8.8.127", "parentHandle": "NET-8-8-8-0-2",
+  "links": [{"rel": "up", "href": "` + mockServer.URL + `/rdap/ip/8.8.8.0/24", "type": "application/rdap+json"}]}`))
+		case "/rdap/ip/8.8.8.0/24":
+			w.Write(fileData)
+		case "/rdap/ip/8.8.8.0/23":
+			w.Write([]byte(`{"objectClassName": "ip network", "handle": "NET-8-0-0-0-0",
+  "startAddress": "8.0.0.0", "endAddress": "8.127.255.255",
+  "links": [{"rel": "down", "href": "` + mockServer.URL + `/rdap/ips?parentHandle=NET-8-0-0-0-0"}]}`))
+		case "/rdap/ips?parentHandle=NET-8-0-0-0-0":
+			w.Write([]byte(`{"ipSearchResults": [
+  {"objectClassName": "ip network", "handle": "NET-8-8-8-0-2"},
+  {"objectClassName": "ip network", "handle": "NET-8-8-4-0-1"}
+]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	bootstrapClient := bootstrap.NewBootstrapClient(mockServer.Client(), "")
+	if err := bootstrapClient.AddOverride(bootstrap.IPv4, "8.0.0.0/8", mockServer.URL+"/rdap/"); err != nil {
+		t.Fatalf("failed to add override: %v", err)
+	}
+	client := NewClient(mockServer.Client(), bootstrapClient)
+	ctx := context.Background()
+
+	chain, err := client.GetIPNetworkChain(ctx, netip.MustParseAddr("8.8.8.8"))
+	if err != nil {
+		t.Fatalf("Failed to get network chain: %v", err)
+	}
+
+	var handles []string
+	for _, network := range chain {
+		handles = append(handles, network.Handle)
+	}
+	expectedHandles := []string{"NET-CUST", "NET-8-8-8-0-2", "NET-8-0-0-0-0"}
+	if !cmp.Equal(handles, expectedHandles) {
+		t.Errorf("unexpected chain %s", cmp.Diff(expectedHandles, handles))
+	}
+
+	// the up link is followed, the parent of GOGL is found by its prefix
+	expectedPaths := []string{"/rdap/ip/8.8.8.8", "/rdap/ip/8.8.8.0/24", "/rdap/ip/8.8.8.0/23"}
+	if !cmp.Equal(requestPaths, expectedPaths) {
+		t.Errorf("unexpected request paths %s", cmp.Diff(expectedPaths, requestPaths))
+	}
+
+	children, err := client.GetChildNetworks(ctx, chain[2])
+	if err != nil {
+		t.Fatalf("Failed to get child networks: %v", err)
+	}
+	if len(children) != 2 || children[1].Handle != "NET-8-8-4-0-1" {
+		t.Errorf("unexpected child networks %+v", children)
+	}
+
+	if _, err := client.GetChildNetworks(ctx, chain[0]); !errors.Is(err, ErrNoLink) {
+		t.Errorf("Expected ErrNoLink, got %v", err)
+	}
+}
+
+func TestGetIPNetworkChainCycle(t *testing.T) {
+	var requests int
+	var mockServer *httptest.Server
+	mockServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/rdap+json")
+
+		// NET-A and NET-B are each other's parent
+		switch r.URL.RequestURI() {
+		case "/rdap/ip/192.0.2.1", "/rdap/ip/192.0.2.0/24":
+			w.Write([]byte(`{"objectClassName": "ip network", "handle": "NET-A",
+  "links": [{"rel": "up", "href": "` + mockServer.URL + `/rdap/ip/192.0.0.0/16"}]}`))
+		case "/rdap/ip/192.0.0.0/16":
+			w.Write([]byte(`{"objectClassName": "ip network", "handle": "NET-B",
+  "links": [{"rel": "up", "href": "` + mockServer.URL + `/rdap/ip/192.0.2.0/24"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockServer.Close()
+
+	bootstrapClient := bootstrap.NewBootstrapClient(mockServer.Client(), "")
+	if err := bootstrapClient.AddOverride(bootstrap.IPv4, "192.0.0.0/8", mockServer.URL+"/rdap/"); err != nil {
+		t.Fatalf("failed to add override: %v", err)
+	}
+	client := NewClient(mockServer.Client(), bootstrapClient)
+
+	chain, err := client.GetIPNetworkChain(context.Background(), netip.MustParseAddr("192.0.2.1"))
+	if !errors.Is(err, ErrNetworkCycle) {
+		t.Errorf("Expected ErrNetworkCycle, got %v", err)
+	}
+	if len(chain) != 2 || chain[0].Handle != "NET-A" || chain[1].Handle != "NET-B" {
+		t.Errorf("unexpected chain %+v", chain)
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestParentPrefix(t *testing.T) {
+	tests := []struct {
+		start, end string
+		expected   string
+	}{
+		{"8.8.8.0", "8.8.8.255", "8.8.8.0/23"},
+		{"8.8.8.0", "8.8.8.127", "8.8.8.0/24"},
+		{"23.0.0.0", "23.2.255.255", "23.0.0.0/14"},
+		{"2001:db8::", "2001:db8:ffff:ffff:ffff:ffff:ffff:ffff", "2001:db8::/31"},
+	}
+
+	for _, tt := range tests {
+		prefix, ok := parentPrefix(&IPNetwork{StartAddress: tt.start, EndAddress: tt.end})
+		if !ok || prefix.String() != tt.expected {
+			t.Errorf("%s-%s: Expected %s, got %s", tt.start, tt.end, tt.expected, prefix)
+		}
+	}
+}
@@ -1,27 +1,189 @@
 package openrdap
 
+import (
+	"net/netip"
+	"reflect"
+)
+
 // IPNetwork represents information of an IP Network.
 //
 // IPNetwork is a topmost RDAP response object.
 // https://datatracker.ietf.org/doc/html/rfc7483#section-5.4
 type IPNetwork struct {
 	Common
-	Conformance     []string `json:"rdapConformance"`
-	ObjectClassName string   `json:"objectClassName"`
-	Notices         []Notice `json:"notices"`
-
-	Handle       string   `json:"handle"`
-	StartAddress string   `json:"startAddress"`
-	EndAddress   string   `json:"endAddress"`
-	IPVersion    string   `json:"ipVersion"`
-	Name         string   `json:"name"`
-	Type         string   `json:"type"`
-	Country      string   `json:"country"`
-	ParentHandle string   `json:"parentHandle"`
-	Status       []string `json:"status"`
-	Entities     []Entity `json:"entities"`
-	Remarks      []Remark `json:"remarks"`
-	Links        []Link   `json:"links"`
-	Port43       string   `json:"port43"`
-	Events       []Event  `json:"events"`
+	Conformance     []string `json:"rdapConformance,omitempty"`
+	ObjectClassName string   `json:"objectClassName,omitempty"`
+	Notices         []Notice `json:"notices,omitempty"`
+
+	Handle       string   `json:"handle,omitempty"`
+	StartAddress string   `json:"startAddress,omitempty"`
+	EndAddress   string   `json:"endAddress,omitempty"`
+	IPVersion    string   `json:"ipVersion,omitempty"`
+	Name         string   `json:"name,omitempty"`
+	Type         string   `json:"type,omitempty"`
+	Country      string   `json:"country,omitempty"`
+	ParentHandle string   `json:"parentHandle,omitempty"`
+	Status       []Status `json:"status,omitempty"`
+	Entities     []Entity `json:"entities,omitempty"`
+	Remarks      []Remark `json:"remarks,omitempty"`
+	Links        []Link   `json:"links,omitempty"`
+	Port43       string   `json:"port43,omitempty"`
+	Events       []Event  `json:"events,omitempty"`
+
+	// CIDRs is the range of the network as prefixes, from the cidr0
+	// extension.
+	//
+	// https://bitbucket.org/nroregistries/rdap-extensions/src/master/cidr0.md
+	CIDRs []CIDR `json:"cidr0_cidrs,omitempty"`
+
+	// Extensions holds the members of extensions without a field above.
+	Extensions Extensions `json:"-"`
+}
+
+// CIDR is a prefix of the cidr0 extension. Only one of V4Prefix and V6Prefix
+// is set.
+type CIDR struct {
+	V4Prefix string `json:"v4prefix,omitempty"`
+	V6Prefix string `json:"v6prefix,omitempty"`
+	Length   int    `json:"length"`
+}
+
+// Prefix returns the CIDR as netip.Prefix, reporting false if it is invalid.
+func (c CIDR) Prefix() (netip.Prefix, bool) {
+	address := c.V4Prefix
+	if address == "" {
+		address = c.V6Prefix
+	}
+	addr, err := netip.ParseAddr(address)
+	if err != nil || c.Length < 0 || c.Length > addr.BitLen() {
+		return netip.Prefix{}, false
+	}
+	return netip.PrefixFrom(addr, c.Length).Masked(), true
+}
+
+// HasStatus reports whether the network has the given status.
+func (n *IPNetwork) HasStatus(status Status) bool {
+	return HasStatus(n, status)
+}
+
+// GetEventByName returns the first event of the network with the given action.
+func (n *IPNetwork) GetEventByName(name string) *Event {
+	return EventByAction(n, name)
+}
+
+// GetEntityFromRole returns the first entity of the network with the given
+// role, searching nested entities at any depth.
+func (n *IPNetwork) GetEntityFromRole(role string) *Entity {
+	return EntityByRole(n, role)
+}
+
+// StartAddr returns StartAddress as netip.Addr, or the zero Addr if it is
+// missing or invalid.
+func (n *IPNetwork) StartAddr() netip.Addr {
+	addr, _ := netip.ParseAddr(n.StartAddress)
+	return addr.Unmap()
+}
+
+// EndAddr returns EndAddress as netip.Addr, or the zero Addr if it is
+// missing or invalid.
+func (n *IPNetwork) EndAddr() netip.Addr {
+	addr, _ := netip.ParseAddr(n.EndAddress)
+	return addr.Unmap()
+}
+
+// addrRange returns the first and last address of the network. Networks
+// without a valid start and end address use the range of their cidr0
+// prefixes.
+func (n *IPNetwork) addrRange() (netip.Addr, netip.Addr, bool) {
+	start, end := n.StartAddr(), n.EndAddr()
+	if start.IsValid() && end.IsValid() && start.BitLen() == end.BitLen() && !end.Less(start) {
+		return start, end, true
+	}
+
+	var first, last netip.Addr
+	for _, cidr := range n.CIDRs {
+		prefix, ok := cidr.Prefix()
+		if !ok || (first.IsValid() && prefix.Addr().BitLen() != first.BitLen()) {
+			return netip.Addr{}, netip.Addr{}, false
+		}
+		if !first.IsValid() || prefix.Addr().Less(first) {
+			first = prefix.Addr()
+		}
+		if l := lastAddr(prefix); !last.IsValid() || last.Less(l) {
+			last = l
+		}
+	}
+	return first, last, first.IsValid()
+}
+
+// Contains reports whether addr is within the range of the network.
+// IPv4-mapped IPv6 addresses are treated as IPv4 addresses.
+func (n *IPNetwork) Contains(addr netip.Addr) bool {
+	start, end, ok := n.addrRange()
+	addr = addr.Unmap().WithZone("")
+	if !ok || addr.BitLen() != start.BitLen() {
+		return false
+	}
+	return !addr.Less(start) && !end.Less(addr)
+}
+
+// Overlaps reports whether the ranges of the networks have any address in
+// common.
+func (n *IPNetwork) Overlaps(other *IPNetwork) bool {
+	start, end, ok := n.addrRange()
+	otherStart, otherEnd, otherOK := other.addrRange()
+	if !ok || !otherOK || start.BitLen() != otherStart.BitLen() {
+		return false
+	}
+	return !end.Less(otherStart) && !otherEnd.Less(start)
+}
+
+// Prefixes returns the minimal list of prefixes covering exactly the range of
+// the network, e.g. 192.0.2.0/24 and 192.0.3.0/25 for 192.0.2.0 to
+// 192.0.3.127. It returns nil if the network has no valid range.
+func (n *IPNetwork) Prefixes() []netip.Prefix {
+	start, end, ok := n.addrRange()
+	if !ok {
+		return nil
+	}
+	return rangePrefixes(start, end)
+}
+
+// rangePrefixes splits the range from start to end into the minimal list of
+// prefixes.
+func rangePrefixes(start, end netip.Addr) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for {
+		// the largest prefix aligned at start which does not extend past end
+		bits := 0
+		for netip.PrefixFrom(start, bits).Masked().Addr() != start || end.Less(lastAddr(netip.PrefixFrom(start, bits))) {
+			bits++
+		}
+		prefix := netip.PrefixFrom(start, bits)
+		prefixes = append(prefixes, prefix)
+
+		last := lastAddr(prefix)
+		if last == end {
+			return prefixes
+		}
+		start = last.Next()
+	}
+}
+
+// UnmarshalJSON for IPNetwork to keep extension members
+func (n *IPNetwork) UnmarshalJSON(data []byte) error {
+	type Alias IPNetwork
+	extensions, err := unmarshalObject(data, (*Alias)(n), reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+
+	n.Extensions = extensions
+	return nil
+}
+
+// MarshalJSON for IPNetwork to write back extension members
+func (n IPNetwork) MarshalJSON() ([]byte, error) {
+	type Alias IPNetwork
+	return marshalObject(Alias(n), n.Extensions)
 }
@@ -0,0 +1,118 @@
+package openrdap
+
+import (
+	"encoding/json"
+	"net/netip"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestIPNetworkPrefixes(t *testing.T) {
+	tests := []struct {
+		name     string
+		network  IPNetwork
+		expected []string
+	}{
+		{
+			name:     "Single prefix",
+			network:  IPNetwork{StartAddress: "8.8.8.0", EndAddress: "8.8.8.255"},
+			expected: []string{"8.8.8.0/24"},
+		},
+		{
+			name:     "Unaligned range",
+			network:  IPNetwork{StartAddress: "192.0.2.0", EndAddress: "192.0.3.127"},
+			expected: []string{"192.0.2.0/24", "192.0.3.0/25"},
+		},
+		{
+			name:     "Unaligned start",
+			network:  IPNetwork{StartAddress: "10.0.0.5", EndAddress: "10.0.0.16"},
+			expected: []string{"10.0.0.5/32", "10.0.0.6/31", "10.0.0.8/29", "10.0.0.16/32"},
+		},
+		{
+			name:     "Single address",
+			network:  IPNetwork{StartAddress: "2001:db8::1", EndAddress: "2001:db8::1"},
+			expected: []string{"2001:db8::1/128"},
+		},
+		{
+			name:     "All IPv4",
+			network:  IPNetwork{StartAddress: "0.0.0.0", EndAddress: "255.255.255.255"},
+			expected: []string{"0.0.0.0/0"},
+		},
+		{
+			name:     "IPv6",
+			network:  IPNetwork{StartAddress: "2600::", EndAddress: "2600:0:0:0:ffff:ffff:ffff:ffff"},
+			expected: []string{"2600::/64"},
+		},
+		{
+			name: "cidr0 only",
+			network: IPNetwork{CIDRs: []CIDR{
+				{V4Prefix: "198.51.100.0", Length: 24},
+				{V4Prefix: "198.51.101.0", Length: 25},
+			}},
+			expected: []string{"198.51.100.0/24", "198.51.101.0/25"},
+		},
+		{
+			name:     "Invalid",
+			network:  IPNetwork{StartAddress: "8.8.8.255", EndAddress: "8.8.8.0"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var result []string
+			for _, prefix := range tt.network.Prefixes() {
+				result = append(result, prefix.String())
+			}
+			if !cmp.Equal(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestIPNetworkContains(t *testing.T) {
+	fileData, err := os.ReadFile("test/example_ip_8888.json")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var network IPNetwork
+	if err := json.Unmarshal(fileData, &network); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if prefix, ok := network.CIDRs[0].Prefix(); !ok || prefix != netip.MustParsePrefix("8.8.8.0/24") {
+		t.Errorf("unexpected cidr0 prefix %v", network.CIDRs)
+	}
+
+	for addr, expected := range map[string]bool{
+		"8.8.8.8":         true,
+		"8.8.8.0":         true,
+		"8.8.8.255":       true,
+		"::ffff:8.8.8.8":  true,
+		"8.8.9.0":         false,
+		"2001:4860::8888": false,
+	} {
+		if result := network.Contains(netip.MustParseAddr(addr)); result != expected {
+			t.Errorf("Contains(%s): expected %v, got %v", addr, expected, result)
+		}
+	}
+
+	tests := []struct {
+		other    IPNetwork
+		expected bool
+	}{
+		{IPNetwork{StartAddress: "8.0.0.0", EndAddress: "8.127.255.255"}, true},
+		{IPNetwork{StartAddress: "8.8.8.255", EndAddress: "8.8.9.255"}, true},
+		{IPNetwork{StartAddress: "8.8.9.0", EndAddress: "8.8.9.255"}, false},
+		{IPNetwork{StartAddress: "2001:db8::", EndAddress: "2001:db8::ffff"}, false},
+	}
+	for _, tt := range tests {
+		if result := network.Overlaps(&tt.other); result != tt.expected {
+			t.Errorf("Overlaps(%s-%s): expected %v, got %v", tt.other.StartAddress, tt.other.EndAddress, tt.expected, result)
+		}
+	}
+}
@@ -1,32 +1,71 @@
 package openrdap
 
+import "reflect"
+
 // Nameserver represents information of a DNS nameserver.
 //
 // Nameserver is a topmost RDAP response object.
 // https://datatracker.ietf.org/doc/html/rfc7483#section-5.2
 type Nameserver struct {
 	Common
-	Conformance     []string `json:"rdapConformance"`
-	ObjectClassName string
-	Notices         []Notice
-
-	Handle      string
-	LDHName     string `json:"ldhName"`
-	UnicodeName string
-
-	IPAddresses *IPAddressSet `json:"ipAddresses"`
-
-	Entities []Entity
-	Status   []string
-	Remarks  []Remark
-	Links    []Link
-	Port43   string
-	Events   []Event
+	Conformance     []string `json:"rdapConformance,omitempty"`
+	ObjectClassName string   `json:"objectClassName,omitempty"`
+	Notices         []Notice `json:"notices,omitempty"`
+
+	Handle      string `json:"handle,omitempty"`
+	LDHName     string `json:"ldhName,omitempty"`
+	UnicodeName string `json:"unicodeName,omitempty"`
+
+	IPAddresses *IPAddressSet `json:"ipAddresses,omitempty"`
+
+	Entities []Entity `json:"entities,omitempty"`
+	Status   []Status `json:"status,omitempty"`
+	Remarks  []Remark `json:"remarks,omitempty"`
+	Links    []Link   `json:"links,omitempty"`
+	Port43   string   `json:"port43,omitempty"`
+	Events   []Event  `json:"events,omitempty"`
+
+	// Extensions holds the members of extensions without a field above.
+	Extensions Extensions `json:"-"`
 }
 
 // IPAddressSet is a subfield of Nameserver.
 type IPAddressSet struct {
 	Common
-	V6 []string
-	V4 []string
+	V6 []string `json:"v6,omitempty"`
+	V4 []string `json:"v4,omitempty"`
+}
+
+// HasStatus reports whether the nameserver has the given status.
+func (n *Nameserver) HasStatus(status Status) bool {
+	return HasStatus(n, status)
+}
+
+// GetEventByName returns the first event of the nameserver with the given action.
+func (n *Nameserver) GetEventByName(name string) *Event {
+	return EventByAction(n, name)
+}
+
+// GetEntityFromRole returns the first entity of the nameserver with the given
+// role, searching nested entities at any depth.
+func (n *Nameserver) GetEntityFromRole(role string) *Entity {
+	return EntityByRole(n, role)
+}
+
+// UnmarshalJSON for Nameserver to keep extension members
+func (n *Nameserver) UnmarshalJSON(data []byte) error {
+	type Alias Nameserver
+	extensions, err := unmarshalObject(data, (*Alias)(n), reflect.TypeOf(Alias{}))
+	if err != nil {
+		return err
+	}
+
+	n.Extensions = extensions
+	return nil
+}
+
+// MarshalJSON for Nameserver to write back extension members
+func (n Nameserver) MarshalJSON() ([]byte, error) {
+	type Alias Nameserver
+	return marshalObject(Alias(n), n.Extensions)
 }
@@ -0,0 +1,167 @@
+package openrdap
+
+import "time"
+
+// Object is implemented by the RDAP object classes Domain, Nameserver,
+// Entity, IPNetwork and Autnum, giving access to the members they share.
+//
+// https://datatracker.ietf.org/doc/html/rfc7483#section-5
+type Object interface {
+	GetObjectClassName() string
+	GetHandle() string
+	GetEntities() []Entity
+	GetEvents() []Event
+	GetLinks() []Link
+	GetRemarks() []Remark
+	GetNotices() []Notice
+	GetStatus() []Status
+	GetPort43() string
+	GetExtensions() Extensions
+}
+
+var (
+	_ Object = (*Domain)(nil)
+	_ Object = (*Nameserver)(nil)
+	_ Object = (*Entity)(nil)
+	_ Object = (*IPNetwork)(nil)
+	_ Object = (*Autnum)(nil)
+)
+
+// EventByAction returns the first event of the object with the given action,
+// e.g. EventRegistration, or nil. Actions are compared case-insensitively.
+func EventByAction(o Object, action string) *Event {
+	return eventByAction(o.GetEvents(), action)
+}
+
+// EventTime returns the date of the first event of the object with the given
+// action, e.g. EventExpiration, or false if there is no such event or its
+// date could not be parsed.
+func EventTime(o Object, action string) (time.Time, bool) {
+	event := EventByAction(o, action)
+	if event == nil || event.Time.IsZero() {
+		return time.Time{}, false
+	}
+	return event.Time, true
+}
+
+// RegistrationDate returns the date of the registration event of the object.
+func RegistrationDate(o Object) (time.Time, bool) {
+	return EventTime(o, EventRegistration)
+}
+
+// LastChangedDate returns the date of the last changed event of the object.
+func LastChangedDate(o Object) (time.Time, bool) {
+	return EventTime(o, EventLastChanged)
+}
+
+// ExpirationDate returns the date of the expiration event of the object.
+func ExpirationDate(o Object) (time.Time, bool) {
+	return EventTime(o, EventExpiration)
+}
+
+// Age returns the time elapsed between the registration of the object and
+// now.
+func Age(o Object, now time.Time) (time.Duration, bool) {
+	registered, ok := RegistrationDate(o)
+	if !ok {
+		return 0, false
+	}
+	return now.Sub(registered), true
+}
+
+// TimeUntilExpiry returns the time remaining from now until the object
+// expires. It is negative once the expiration date has passed.
+func TimeUntilExpiry(o Object, now time.Time) (time.Duration, bool) {
+	expires, ok := ExpirationDate(o)
+	if !ok {
+		return 0, false
+	}
+	return expires.Sub(now), true
+}
+
+// EntityByRole returns the first entity of the object with the given role, at
+// any depth, or nil. See FindEntities to get all matches.
+func EntityByRole(o Object, role string) *Entity {
+	var result *Entity
+	WalkEntities(o, func(path EntityPath) error {
+		if path.Entity().HasRole(role) {
+			result = path.Entity()
+			return errStopWalk
+		}
+		return nil
+	})
+	return result
+}
+
+// LinkByRel returns the first link of the object with the given relation,
+// e.g. "self", or nil.
+func LinkByRel(o Object, rel string) *Link {
+	links := o.GetLinks()
+	for i := range links {
+		if links[i].Rel == rel {
+			return &links[i]
+		}
+	}
+	return nil
+}
+
+// HasStatus reports whether the object has the given status. The status is
+// normalized with ParseStatus, so EPP codes are accepted.
+func HasStatus(o Object, status Status) bool {
+	return hasStatus(o.GetStatus(), ParseStatus(string(status)))
+}
+
+func (d *Domain) GetObjectClassName() string { return d.ObjectClassName }
+func (d *Domain) GetHandle() string          { return d.Handle }
+func (d *Domain) GetEntities() []Entity      { return d.Entities }
+func (d *Domain) GetEvents() []Event         { return d.Events }
+func (d *Domain) GetLinks() []Link           { return d.Links }
+func (d *Domain) GetRemarks() []Remark       { return d.Remarks }
+func (d *Domain) GetNotices() []Notice       { return d.Notices }
+func (d *Domain) GetStatus() []Status        { return d.Status }
+func (d *Domain) GetPort43() string          { return d.Port43 }
+func (d *Domain) GetExtensions() Extensions  { return d.Extensions }
+
+func (n *Nameserver) GetObjectClassName() string { return n.ObjectClassName }
+func (n *Nameserver) GetHandle() string          { return n.Handle }
+func (n *Nameserver) GetEntities() []Entity      { return n.Entities }
+func (n *Nameserver) GetEvents() []Event         { return n.Events }
+func (n *Nameserver) GetLinks() []Link           { return n.Links }
+func (n *Nameserver) GetRemarks() []Remark       { return n.Remarks }
+func (n *Nameserver) GetNotices() []Notice       { return n.Notices }
+func (n *Nameserver) GetStatus() []Status        { return n.Status }
+func (n *Nameserver) GetPort43() string          { return n.Port43 }
+func (n *Nameserver) GetExtensions() Extensions  { return n.Extensions }
+
+func (e *Entity) GetObjectClassName() string { return e.ObjectClassName }
+func (e *Entity) GetHandle() string          { return e.Handle }
+func (e *Entity) GetEntities() []Entity      { return e.Entities }
+func (e *Entity) GetEvents() []Event         { return e.Events }
+func (e *Entity) GetLinks() []Link           { return e.Links }
+func (e *Entity) GetRemarks() []Remark       { return e.Remarks }
+func (e *Entity) GetNotices() []Notice       { return e.Notices }
+func (e *Entity) GetStatus() []Status        { return e.Status }
+func (e *Entity) GetPort43() string          { return e.Port43 }
+func (e *Entity) GetExtensions() Extensions  { return e.Extensions }
+
+func (n *IPNetwork) GetObjectClassName() string { return n.ObjectClassName }
+func (n *IPNetwork) GetHandle() string          { return n.Handle }
+func (n *IPNetwork) GetEntities() []Entity      { return n.Entities }
+func (n *IPNetwork) GetEvents() []Event         { return n.Events }
+func (n *IPNetwork) GetLinks() []Link           { return n.Links }
+func (n *IPNetwork) GetRemarks() []Remark       { return n.Remarks }
+func (n *IPNetwork) GetNotices() []Notice       { return n.Notices }
+func (n *IPNetwork) GetStatus() []Status        { return n.Status }
+func (n *IPNetwork) GetPort43() string          { return n.Port43 }
+func (n *IPNetwork) GetExtensions() Extensions  { return n.Extensions }
+
+func (a *Autnum) GetObjectClassName() string { return a.ObjectClassName }
+func (a *Autnum) GetHandle() string          { return a.Handle }
+func (a *Autnum) GetEntities() []Entity      { return a.Entities }
+func (a *Autnum) GetEvents() []Event         { return a.Events }
+func (a *Autnum) GetLinks() []Link           { return a.Links }
+func (a *Autnum) GetRemarks() []Remark       { return a.Remarks }
+func (a *Autnum) GetNotices() []Notice       { return a.Notices }
+func (a *Autnum) GetStatus() []Status        { return a.Status }
+func (a *Autnum) GetPort43() string          { return a.Port43 }
+func (a *Autnum) GetExtensions() Extensions  { return a.Extensions }
@@ -0,0 +1,95 @@
+package openrdap
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestObjectHelpers(t *testing.T) {
+	tests := []struct {
+		name           string
+		path           string
+		object         Object
+		expectedHandle string
+		expectedClass  string
+		expectedSelf   string
+		expectedAbuse  string
+		expectedStatus Status
+		registered     bool
+	}{
+		{
+			name:           "Domain",
+			path:           "test/example_domain_perihwk.json",
+			object:         &Domain{},
+			expectedClass:  "domain",
+			expectedStatus: StatusClientTransferProhibited,
+			registered:     true,
+		},
+		{
+			name:           "IPNetwork",
+			path:           "test/example_ip_8888.json",
+			object:         &IPNetwork{},
+			expectedHandle: "NET-8-8-8-0-2",
+			expectedClass:  "ip network",
+			expectedSelf:   "https://rdap.arin.net/registry/ip/8.8.8.0",
+			expectedAbuse:  "ABUSE5250-ARIN",
+			expectedStatus: StatusActive,
+			registered:     true,
+		},
+		{
+			name:           "Autnum",
+			path:           "test/example_asn_23552.json",
+			object:         &Autnum{},
+			expectedHandle: "AS23552",
+			expectedClass:  "autnum",
+			expectedSelf:   "https://krnic.rdap.apnic.net/autnum/23552",
+			expectedStatus: StatusActive,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileData, err := os.ReadFile(tt.path)
+			if err != nil {
+				t.Fatalf("failed to read file: %v", err)
+			}
+			if err := json.Unmarshal(fileData, tt.object); err != nil {
+				t.Fatalf("failed to unmarshal: %v", err)
+			}
+
+			if tt.expectedHandle != "" && tt.object.GetHandle() != tt.expectedHandle {
+				t.Errorf("Expected handle %s, got %s", tt.expectedHandle, tt.object.GetHandle())
+			}
+			if tt.object.GetObjectClassName() != tt.expectedClass {
+				t.Errorf("Expected class %s, got %s", tt.expectedClass, tt.object.GetObjectClassName())
+			}
+			if tt.expectedSelf != "" {
+				if self := LinkByRel(tt.object, "self"); self == nil || self.Href != tt.expectedSelf {
+					t.Errorf("Expected self link %s, got %+v", tt.expectedSelf, self)
+				}
+			}
+			if tt.expectedAbuse != "" {
+				if abuse := EntityByRole(tt.object, "ABUSE"); abuse == nil || abuse.Handle != tt.expectedAbuse {
+					t.Errorf("Expected abuse entity %s, got %+v", tt.expectedAbuse, abuse)
+				}
+			}
+			if !HasStatus(tt.object, tt.expectedStatus) {
+				t.Errorf("Expected status %s in %v", tt.expectedStatus, tt.object.GetStatus())
+			}
+			if registered := EventByAction(tt.object, EventRegistration) != nil; registered != tt.registered {
+				t.Errorf("Expected registration event: %v, got: %v", tt.registered, registered)
+			}
+		})
+	}
+}
+
+func TestEntityByRolePointer(t *testing.T) {
+	domain := &Domain{Entities: []Entity{{Handle: "R", Roles: []string{"registrar"}}}}
+
+	// the returned entity points into the object, not to a copy
+	EntityByRole(domain, "registrar").Handle = "CHANGED"
+	if domain.Entities[0].Handle != "CHANGED" {
+		t.Errorf("expected the entity of the domain to be returned, got a copy")
+	}
+}
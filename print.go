@@ -2,17 +2,22 @@ package openrdap
 
 import "fmt"
 
+// PrintDomainRDAP writes a few fields of domain to stdout.
+//
+// Deprecated: Use formatter.WriteWHOIS, which writes all fields of any
+// object class in the ICANN WHOIS layout to an io.Writer, or render.Summary
+// and render.Template for other layouts.
 func PrintDomainRDAP(domain *Domain) {
 	fmt.Printf("RegistryDomainID: %s\n", domain.Handle)
 	fmt.Printf("DomainName: %s\n", domain.LDHName)
 
-	if event := domain.GetEventByName("registration"); event != nil {
+	if event := domain.GetEventByName(EventRegistration); event != nil {
 		fmt.Printf("CreatedDate: %s\n", event.Date)
 	}
-	if event := domain.GetEventByName("last changed"); event != nil {
+	if event := domain.GetEventByName(EventLastChanged); event != nil {
 		fmt.Printf("UpdatedDate: %s\n", event.Date)
 	}
-	if event := domain.GetEventByName("expiration"); event != nil {
+	if event := domain.GetEventByName(EventExpiration); event != nil {
 		fmt.Printf("RegistrarExpirationDate: %s\n", event.Date)
 	}
 	fmt.Printf("RegistrarWhoisServer: %s\n", domain.Port43)
@@ -21,14 +26,14 @@ func PrintDomainRDAP(domain *Domain) {
 
 	registrar := domain.GetEntityFromRole("registrar")
 	if registrar != nil {
-		fmt.Printf("Registrar: %s\n", registrar.VCards[0].FullName)
+		fmt.Printf("Registrar: %s\n", registrar.VCard().FullName)
 		fmt.Printf("RegistrarIanaID: %s\n", registrar.Handle)
 	}
 
 	abuse := domain.GetEntityFromRole("abuse")
 	if abuse != nil {
-		fmt.Printf("RegistrarAbuseContactEmail: %s\n", abuse.VCards[0].Email)
-		fmt.Printf("RegistrarAbuseContactPhone: %s\n", abuse.VCards[0].Telephone)
+		fmt.Printf("RegistrarAbuseContactEmail: %s\n", abuse.VCard().Email)
+		fmt.Printf("RegistrarAbuseContactPhone: %s\n", abuse.VCard().Telephone)
 	}
 
 	registrarURL := domain.GetRegistrarURL()
@@ -38,29 +43,32 @@ func PrintDomainRDAP(domain *Domain) {
 
 	registrantEntity := domain.GetEntityFromRole("registrant")
 	if registrantEntity != nil {
-		fmt.Printf("RegistrantOrganization: %s\n", registrantEntity.VCards[0].Org)
-		fmt.Printf("RegistrantState: %+v\n", registrantEntity.VCards[0].Address)
-		fmt.Printf("RegistrantCountry: %+v\n", registrantEntity.VCards[0].Address)
-		fmt.Printf("RegistrantEmail: %s\n", registrantEntity.VCards[0].Email)
+		fmt.Printf("RegistrantOrganization: %s\n", registrantEntity.VCard().Org)
+		fmt.Printf("RegistrantState: %+v\n", registrantEntity.VCard().Address)
+		fmt.Printf("RegistrantCountry: %+v\n", registrantEntity.VCard().Address)
+		fmt.Printf("RegistrantEmail: %s\n", registrantEntity.VCard().Email)
 	}
 
 	adminEntity := domain.GetEntityFromRole("administrative")
 	if adminEntity != nil {
-		fmt.Printf("AdminOrganization: %v\n", adminEntity.VCards[0].Org)
-		fmt.Printf("AdminState: %v\n", adminEntity.VCards[0].Address.Region)
-		fmt.Printf("AdminCountry: %v\n", adminEntity.VCards[0].Address.Country)
-		fmt.Printf("AdminEmail: %v\n", adminEntity.VCards[0].Email)
+		fmt.Printf("AdminOrganization: %v\n", adminEntity.VCard().Org)
+		fmt.Printf("AdminState: %v\n", adminEntity.VCard().Address.Region)
+		fmt.Printf("AdminCountry: %v\n", adminEntity.VCard().Address.Country)
+		fmt.Printf("AdminEmail: %v\n", adminEntity.VCard().Email)
 	}
 
 	techEntity := domain.GetEntityFromRole("technical")
 	if techEntity != nil {
-		fmt.Printf("TechOrganization: %v\n", techEntity.VCards[0].Org)
-		fmt.Printf("TechState: %v\n", techEntity.VCards[0].Address.Region)
-		fmt.Printf("TechCountry: %v\n", techEntity.VCards[0].Address.Country)
-		fmt.Printf("TechEmail: %v\n", techEntity.VCards[0].Email)
+		fmt.Printf("TechOrganization: %v\n", techEntity.VCard().Org)
+		fmt.Printf("TechState: %v\n", techEntity.VCard().Address.Region)
+		fmt.Printf("TechCountry: %v\n", techEntity.VCard().Address.Country)
+		fmt.Printf("TechEmail: %v\n", techEntity.VCard().Email)
 	}
 }
 
+// PrintAutnumRDAP writes asn to stdout.
+//
+// Deprecated: Use formatter.WriteWHOIS or render.Summary.
 func PrintAutnumRDAP(asn *Autnum) {
 	fmt.Printf("%+v\n", asn)
 }
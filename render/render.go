@@ -0,0 +1,190 @@
+// Package render writes RDAP objects in user selected formats: text/template
+// templates, including built-in summaries of domains, IP networks and
+// autonomous system numbers, aligned tables and CSV with selectable columns,
+// and pretty or compact JSON.
+//
+// For the WHOIS layout see the formatter package.
+package render
+
+import (
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/perihwk/openrdap"
+)
+
+var (
+	// ErrUnknownTemplate is returned for names of templates which are not
+	// built in.
+	ErrUnknownTemplate = errors.New("unknown template")
+
+	// ErrUnsupportedObject is returned by Summary for objects without a
+	// built-in template.
+	ErrUnsupportedObject = errors.New("unsupported object")
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+// Funcs returns the functions available in templates parsed with Parse:
+//
+//	event OBJECT ACTION  date of the event with the action, e.g. "registration"
+//	entity OBJECT ROLE   entity with the role at any depth, or nil
+//	vcard ENTITY         first vCard of the entity, empty if there is none
+//	age OBJECT           days since the registration event
+//	tel VALUE            telephone number of a "tel:" URI
+//	join LIST SEP        elements of a slice joined with SEP
+//
+// Dates are formatted as RFC 3339 in UTC, missing values render as "".
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"event":  eventDate,
+		"entity": openrdap.EntityByRole,
+		"vcard":  vcard,
+		"age":    ageDays,
+		"tel":    tel,
+		"join":   join,
+	}
+}
+
+// Parse parses text as a template with Funcs.
+func Parse(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(Funcs()).Parse(text)
+}
+
+// BuiltinNames returns the names of the built-in templates: "asn", "domain"
+// and "ip".
+func BuiltinNames() []string {
+	entries, _ := templatesFS.ReadDir("templates")
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".tmpl"))
+	}
+	return names
+}
+
+// Builtin returns the built-in template with the given name, see
+// BuiltinNames.
+func Builtin(name string) (*template.Template, error) {
+	text, err := templatesFS.ReadFile("templates/" + name + ".tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTemplate, name)
+	}
+	return Parse(name, string(text))
+}
+
+// Template executes tmpl with obj and writes the result, followed by a
+// newline, to w.
+func Template(w io.Writer, tmpl *template.Template, obj any) error {
+	var b strings.Builder
+	if err := tmpl.Execute(&b, obj); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, strings.TrimRight(b.String(), "\n")+"\n")
+	return err
+}
+
+// Summary writes obj with the built-in template of its class: "domain" for
+// a *openrdap.Domain, "ip" for a *openrdap.IPNetwork and "asn" for a
+// *openrdap.Autnum.
+func Summary(w io.Writer, obj openrdap.Object) error {
+	var name string
+	switch obj.(type) {
+	case *openrdap.Domain:
+		name = "domain"
+	case *openrdap.IPNetwork:
+		name = "ip"
+	case *openrdap.Autnum:
+		name = "asn"
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedObject, obj)
+	}
+
+	tmpl, err := Builtin(name)
+	if err != nil {
+		return err
+	}
+	return Template(w, tmpl, obj)
+}
+
+// JSON writes v as JSON, indented by two spaces if pretty is set, followed by
+// a newline.
+func JSON(w io.Writer, v any, pretty bool) error {
+	var data []byte
+	var err error
+	if pretty {
+		data, err = json.MarshalIndent(v, "", "  ")
+	} else {
+		data, err = json.Marshal(v)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// eventDate returns the date of the first event of obj with the given
+// action, or "".
+func eventDate(obj openrdap.Object, action string) string {
+	event := openrdap.EventByAction(obj, action)
+	switch {
+	case event == nil:
+		return ""
+	case event.Time.IsZero():
+		return event.Date
+	default:
+		return event.Time.UTC().Format(time.RFC3339)
+	}
+}
+
+// vcard returns the first vCard of e. Templates get a pointer so the methods
+// of VCard, such as Voice, can be called.
+func vcard(e *openrdap.Entity) *openrdap.VCard {
+	if e == nil {
+		return &openrdap.VCard{}
+	}
+	v := e.VCard()
+	return &v
+}
+
+// ageDays returns the whole days since obj was registered, or "".
+func ageDays(obj openrdap.Object) string {
+	age, ok := openrdap.Age(obj, time.Now())
+	if !ok {
+		return ""
+	}
+	return strconv.Itoa(int(age.Hours() / 24))
+}
+
+// tel returns the number of a "tel:" URI, e.g. "+1.5555551212" for
+// "tel:+1.5555551212;ext=1234". Other values are returned unchanged.
+func tel(value string) string {
+	if number, ok := strings.CutPrefix(value, "tel:"); ok {
+		number, _, _ = strings.Cut(number, ";")
+		return number
+	}
+	return value
+}
+
+// join joins the elements of a slice, formatted with fmt.Sprint, with sep.
+func join(list any, sep string) string {
+	v := reflect.ValueOf(list)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Sprint(list)
+	}
+
+	parts := make([]string, v.Len())
+	for i := range parts {
+		parts[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(parts, sep)
+}
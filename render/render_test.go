@@ -0,0 +1,201 @@
+package render
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/perihwk/openrdap"
+)
+
+func decodeFile(t *testing.T, name string) openrdap.Object {
+	t.Helper()
+
+	fileData, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+	obj, err := openrdap.Decode(fileData)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+	return obj
+}
+
+func TestSummary(t *testing.T) {
+	testCases := []struct {
+		file  string
+		lines []string
+	}{
+		{
+			file: "../test/example_domain_perihwk.json",
+			lines: []string{
+				"Domain:       PERIHWK.COM",
+				"Status:       client transfer prohibited",
+				"Registered:   2019-02-05T03:32:53Z",
+				"Expires:      2025-02-05T03:32:53Z",
+				"Registrar:    NameCheap, Inc.",
+				"Abuse email:  abuse@namecheap.com",
+				"Abuse phone:  +1.6613102107",
+				"Nameservers:  PENNY.NS.CLOUDFLARE.COM, TONY.NS.CLOUDFLARE.COM",
+				"DNSSEC:       signed",
+			},
+		},
+		{
+			file: "../test/example_ip_8888.json",
+			lines: []string{
+				"Network:      GOGL",
+				"Range:        8.8.8.0 - 8.8.8.255",
+				"CIDR:         8.8.8.0/24",
+				"Registrant:   Google LLC",
+				"Abuse email:  network-abuse@google.com",
+			},
+		},
+		{
+			file: "../test/example_asn_23552.json",
+			lines: []string{
+				"ASN:          AS23552",
+				"Name:         KORNU-AS-KR-KR",
+				"Country:      KR",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			var b strings.Builder
+			if err := Summary(&b, decodeFile(t, tc.file)); err != nil {
+				t.Fatalf("failed to render: %v", err)
+			}
+			for _, line := range tc.lines {
+				if !strings.Contains(b.String(), line+"\n") {
+					t.Errorf("expected line %q in output:\n%s", line, b.String())
+				}
+			}
+		})
+	}
+
+	err := Summary(&strings.Builder{}, &openrdap.Entity{})
+	if !errors.Is(err, ErrUnsupportedObject) {
+		t.Errorf("expected ErrUnsupportedObject, got %v", err)
+	}
+}
+
+func TestBuiltin(t *testing.T) {
+	if names := BuiltinNames(); !cmp.Equal(names, []string{"asn", "domain", "ip"}) {
+		t.Errorf("unexpected built-in templates %v", names)
+	}
+	if _, err := Builtin("nameserver"); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("expected ErrUnknownTemplate, got %v", err)
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	tmpl, err := Parse("custom", `{{.LDHName}} {{with entity . "registrar"}}{{(vcard .).FullName}}{{else}}-{{end}} {{(vcard (entity . "abuse")).Email}}|{{event . "expiration"}}`)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+
+	domain := &openrdap.Domain{
+		LDHName:  "example.com",
+		Entities: []openrdap.Entity{{Roles: []string{"registrar"}}},
+	}
+	var b strings.Builder
+	if err := Template(&b, tmpl, domain); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if expected := "example.com  |\n"; b.String() != expected {
+		t.Errorf("Expected %q, got %q", expected, b.String())
+	}
+}
+
+var tableObjects = []openrdap.Object{
+	&openrdap.Domain{
+		ObjectClassName: "domain",
+		Handle:          "D-1",
+		LDHName:         "example.com",
+		Status:          []openrdap.Status{openrdap.StatusActive},
+		Nameservers:     []openrdap.Nameserver{{LDHName: "ns1.example.com"}, {LDHName: "ns2.example.com"}},
+	},
+	&openrdap.SearchResults{
+		IPNetworks: []openrdap.IPNetwork{
+			{ObjectClassName: "ip network", Handle: "NET-1", StartAddress: "192.0.2.0", EndAddress: "192.0.2.255", Country: "AA"},
+		},
+		Autnums: []openrdap.Autnum{
+			{ObjectClassName: "autnum", Handle: "AS64496", StartAutnum: 64496, EndAutnum: 64496, Name: "EXAMPLE"},
+		},
+	},
+}
+
+func TestTable(t *testing.T) {
+	columns, err := Columns("class", "handle", "name", "range", "nameservers")
+	if err != nil {
+		t.Fatalf("failed to select columns: %v", err)
+	}
+
+	var b strings.Builder
+	if err := Table(&b, columns, tableObjects...); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+
+	expected := `CLASS       HANDLE   NAME         RANGE                    NAMESERVERS
+domain      D-1      example.com                           ns1.example.com ns2.example.com
+ip network  NET-1                 192.0.2.0 - 192.0.2.255
+autnum      AS64496  EXAMPLE      64496
+`
+	if diff := cmp.Diff(expected, b.String()); diff != "" {
+		t.Errorf("unexpected table (-want +got):\n%s", diff)
+	}
+}
+
+func TestCSV(t *testing.T) {
+	columns, err := Columns("Handle", "status", "cidr", "country")
+	if err != nil {
+		t.Fatalf("failed to select columns: %v", err)
+	}
+
+	var b strings.Builder
+	if err := CSV(&b, columns, tableObjects...); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+
+	expected := `handle,status,cidr,country
+D-1,active,,
+NET-1,,192.0.2.0/24,AA
+AS64496,,,
+`
+	if diff := cmp.Diff(expected, b.String()); diff != "" {
+		t.Errorf("unexpected CSV (-want +got):\n%s", diff)
+	}
+
+	if _, err := Columns("handle", "bogus"); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("expected ErrUnknownColumn, got %v", err)
+	}
+}
+
+func TestJSON(t *testing.T) {
+	autnum := &openrdap.Autnum{Handle: "AS64496", StartAutnum: 64496, EndAutnum: 64496}
+
+	var compact, pretty strings.Builder
+	if err := JSON(&compact, autnum, false); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+	if err := JSON(&pretty, autnum, true); err != nil {
+		t.Fatalf("failed to render: %v", err)
+	}
+
+	if expected := `{"handle":"AS64496","startAutnum":64496,"endAutnum":64496}` + "\n"; compact.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, compact.String())
+	}
+	expected := `{
+  "handle": "AS64496",
+  "startAutnum": 64496,
+  "endAutnum": 64496
+}
+`
+	if pretty.String() != expected {
+		t.Errorf("Expected %s, got %s", expected, pretty.String())
+	}
+}
@@ -0,0 +1,211 @@
+package render
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/perihwk/openrdap"
+)
+
+// ErrUnknownColumn is returned by Columns for names of columns which do not
+// exist.
+var ErrUnknownColumn = errors.New("unknown column")
+
+// A Column is a value extracted from an object for tables and CSV. Value
+// returns "" for objects which do not have the value.
+type Column struct {
+	Name  string
+	Value func(obj openrdap.Object) string
+}
+
+// DefaultColumns are the columns used if none are selected.
+var DefaultColumns = []string{"class", "handle", "name", "status", "registered", "expires"}
+
+// columns holds the columns by name, in the order listed by ColumnNames.
+var columns = []Column{
+	{"class", func(obj openrdap.Object) string { return obj.GetObjectClassName() }},
+	{"handle", func(obj openrdap.Object) string { return obj.GetHandle() }},
+	{"name", objectName},
+	{"status", func(obj openrdap.Object) string { return join(obj.GetStatus(), ", ") }},
+	{"registered", func(obj openrdap.Object) string { return eventDate(obj, openrdap.EventRegistration) }},
+	{"updated", func(obj openrdap.Object) string { return eventDate(obj, openrdap.EventLastChanged) }},
+	{"expires", func(obj openrdap.Object) string { return eventDate(obj, openrdap.EventExpiration) }},
+	{"registrar", func(obj openrdap.Object) string {
+		return vcard(openrdap.EntityByRole(obj, "registrar")).FullName
+	}},
+	{"abuse-email", func(obj openrdap.Object) string {
+		return vcard(openrdap.EntityByRole(obj, "abuse")).Email
+	}},
+	{"nameservers", func(obj openrdap.Object) string {
+		if d, ok := obj.(*openrdap.Domain); ok {
+			return strings.Join(d.GetNameServersDNS(), " ")
+		}
+		return ""
+	}},
+	{"range", objectRange},
+	{"cidr", func(obj openrdap.Object) string {
+		if n, ok := obj.(*openrdap.IPNetwork); ok {
+			return join(n.Prefixes(), " ")
+		}
+		return ""
+	}},
+	{"country", func(obj openrdap.Object) string {
+		switch obj := obj.(type) {
+		case *openrdap.IPNetwork:
+			return obj.Country
+		case *openrdap.Autnum:
+			return obj.Country
+		}
+		return ""
+	}},
+	{"port43", func(obj openrdap.Object) string { return obj.GetPort43() }},
+}
+
+// ColumnNames returns the names of all columns.
+func ColumnNames() []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+	return names
+}
+
+// Columns returns the columns with the given names, or DefaultColumns if
+// names is empty. Names are matched case-insensitively.
+func Columns(names ...string) ([]Column, error) {
+	if len(names) == 0 {
+		names = DefaultColumns
+	}
+
+	var selected []Column
+	for _, name := range names {
+		i := columnIndex(strings.TrimSpace(name))
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownColumn, name)
+		}
+		selected = append(selected, columns[i])
+	}
+	return selected, nil
+}
+
+func columnIndex(name string) int {
+	for i, column := range columns {
+		if strings.EqualFold(column.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Table writes objs as a table aligned with spaces, with a header of the
+// upper case column names. Search results are written as one row per
+// result.
+func Table(w io.Writer, columns []Column, objs ...openrdap.Object) error {
+	var b strings.Builder
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = strings.ToUpper(column.Name)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+
+	for _, row := range rows(columns, objs) {
+		for i, value := range row {
+			// tabs and newlines would break the alignment
+			row[i] = strings.Join(strings.Fields(value), " ")
+		}
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	// drop the padding of empty trailing cells
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// CSV writes objs as CSV with a header of the column names. Search results
+// are written as one row per result.
+func CSV(w io.Writer, columns []Column, objs ...openrdap.Object) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, column := range columns {
+		header[i] = column.Name
+	}
+	cw.Write(header)
+	cw.WriteAll(rows(columns, objs))
+	return cw.Error()
+}
+
+// rows returns the values of columns for each object, expanding search
+// results.
+func rows(columns []Column, objs []openrdap.Object) [][]string {
+	var rows [][]string
+	for _, obj := range objs {
+		if results, ok := obj.(*openrdap.SearchResults); ok {
+			rows = append(rows, rowsOf(columns, results.Objects())...)
+			continue
+		}
+		rows = append(rows, rowsOf(columns, []openrdap.Object{obj})...)
+	}
+	return rows
+}
+
+func rowsOf(columns []Column, objs []openrdap.Object) [][]string {
+	rows := make([][]string, len(objs))
+	for i, obj := range objs {
+		rows[i] = make([]string, len(columns))
+		for j, column := range columns {
+			rows[i][j] = column.Value(obj)
+		}
+	}
+	return rows
+}
+
+// objectName returns the domain or nameserver name, the network or autnum
+// name, or the full name of an entity.
+func objectName(obj openrdap.Object) string {
+	switch obj := obj.(type) {
+	case *openrdap.Domain:
+		return obj.LDHName
+	case *openrdap.Nameserver:
+		return obj.LDHName
+	case *openrdap.IPNetwork:
+		return obj.Name
+	case *openrdap.Autnum:
+		return obj.Name
+	case *openrdap.Entity:
+		return obj.VCard().FullName
+	}
+	return ""
+}
+
+// objectRange returns the address range of a network or the number range of
+// an autnum.
+func objectRange(obj openrdap.Object) string {
+	switch obj := obj.(type) {
+	case *openrdap.IPNetwork:
+		if obj.StartAddress == "" {
+			return ""
+		}
+		return obj.StartAddress + " - " + obj.EndAddress
+	case *openrdap.Autnum:
+		if obj.EndAutnum > obj.StartAutnum {
+			return strconv.FormatUint(uint64(obj.StartAutnum), 10) + " - " + strconv.FormatUint(uint64(obj.EndAutnum), 10)
+		}
+		return strconv.FormatUint(uint64(obj.StartAutnum), 10)
+	}
+	return ""
+}
@@ -0,0 +1,211 @@
+package openrdap
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Status indicates the state of a registered object.
+//
+// Values hold the string of the response unchanged. The helpers, such as
+// HasStatus, Registered and EPP, compare normalized values (see Normalize),
+// so "Client Transfer Prohibited", "client_transfer_prohibited" and the EPP
+// code "clientTransferProhibited" all match StatusClientTransferProhibited.
+//
+// https://tools.ietf.org/html/rfc7483#section-4.6
+type Status string
+
+// Status values registered by RFC 8056 and later RFCs.
+//
+// https://www.iana.org/assignments/rdap-json-values/rdap-json-values.xhtml
+const (
+	StatusValidated                Status = "validated"
+	StatusRenewProhibited          Status = "renew prohibited"
+	StatusUpdateProhibited         Status = "update prohibited"
+	StatusTransferProhibited       Status = "transfer prohibited"
+	StatusDeleteProhibited         Status = "delete prohibited"
+	StatusProxy                    Status = "proxy"
+	StatusPrivate                  Status = "private"
+	StatusRemoved                  Status = "removed"
+	StatusObscured                 Status = "obscured"
+	StatusAssociated               Status = "associated"
+	StatusActive                   Status = "active"
+	StatusInactive                 Status = "inactive"
+	StatusLocked                   Status = "locked"
+	StatusPendingCreate            Status = "pending create"
+	StatusPendingRenew             Status = "pending renew"
+	StatusPendingTransfer          Status = "pending transfer"
+	StatusPendingUpdate            Status = "pending update"
+	StatusPendingDelete            Status = "pending delete"
+	StatusAddPeriod                Status = "add period"
+	StatusAutoRenewPeriod          Status = "auto renew period"
+	StatusClientDeleteProhibited   Status = "client delete prohibited"
+	StatusClientHold               Status = "client hold"
+	StatusClientRenewProhibited    Status = "client renew prohibited"
+	StatusClientTransferProhibited Status = "client transfer prohibited"
+	StatusClientUpdateProhibited   Status = "client update prohibited"
+	StatusPendingRestore           Status = "pending restore"
+	StatusRedemptionPeriod         Status = "redemption period"
+	StatusRenewPeriod              Status = "renew period"
+	StatusServerDeleteProhibited   Status = "server delete prohibited"
+	StatusServerRenewProhibited    Status = "server renew prohibited"
+	StatusServerTransferProhibited Status = "server transfer prohibited"
+	StatusServerUpdateProhibited   Status = "server update prohibited"
+	StatusServerHold               Status = "server hold"
+	StatusTransferPeriod           Status = "transfer period"
+	StatusAdministrative           Status = "administrative"
+	StatusReserved                 Status = "reserved"
+)
+
+// registeredStatuses is the set of the status values above.
+var registeredStatuses = map[Status]bool{
+	StatusValidated: true, StatusRenewProhibited: true, StatusUpdateProhibited: true,
+	StatusTransferProhibited: true, StatusDeleteProhibited: true, StatusProxy: true,
+	StatusPrivate: true, StatusRemoved: true, StatusObscured: true,
+	StatusAssociated: true, StatusActive: true, StatusInactive: true,
+	StatusLocked: true, StatusPendingCreate: true, StatusPendingRenew: true,
+	StatusPendingTransfer: true, StatusPendingUpdate: true, StatusPendingDelete: true,
+	StatusAddPeriod: true, StatusAutoRenewPeriod: true, StatusClientDeleteProhibited: true,
+	StatusClientHold: true, StatusClientRenewProhibited: true, StatusClientTransferProhibited: true,
+	StatusClientUpdateProhibited: true, StatusPendingRestore: true, StatusRedemptionPeriod: true,
+	StatusRenewPeriod: true, StatusServerDeleteProhibited: true, StatusServerRenewProhibited: true,
+	StatusServerTransferProhibited: true, StatusServerUpdateProhibited: true, StatusServerHold: true,
+	StatusTransferPeriod: true, StatusAdministrative: true, StatusReserved: true,
+}
+
+// Normalize returns the status normalized with ParseStatus, e.g.
+// StatusClientHold for "clientHold".
+func (s Status) Normalize() Status {
+	return ParseStatus(string(s))
+}
+
+// Registered reports whether the status is one of the values registered with
+// IANA, after normalization.
+func (s Status) Registered() bool {
+	return registeredStatuses[s.Normalize()]
+}
+
+// eppStatuses maps EPP status codes (RFC 5731, 5732, 5733 and 3915) to RDAP
+// status values, following RFC 8056 section 2.
+var eppStatuses = map[string]Status{
+	"addPeriod":                StatusAddPeriod,
+	"autoRenewPeriod":          StatusAutoRenewPeriod,
+	"clientDeleteProhibited":   StatusClientDeleteProhibited,
+	"clientHold":               StatusClientHold,
+	"clientRenewProhibited":    StatusClientRenewProhibited,
+	"clientTransferProhibited": StatusClientTransferProhibited,
+	"clientUpdateProhibited":   StatusClientUpdateProhibited,
+	"inactive":                 StatusInactive,
+	"linked":                   StatusAssociated,
+	"ok":                       StatusActive,
+	"pendingCreate":            StatusPendingCreate,
+	"pendingDelete":            StatusPendingDelete,
+	"pendingRenew":             StatusPendingRenew,
+	"pendingRestore":           StatusPendingRestore,
+	"pendingTransfer":          StatusPendingTransfer,
+	"pendingUpdate":            StatusPendingUpdate,
+	"redemptionPeriod":         StatusRedemptionPeriod,
+	"renewPeriod":              StatusRenewPeriod,
+	"serverDeleteProhibited":   StatusServerDeleteProhibited,
+	"serverHold":               StatusServerHold,
+	"serverRenewProhibited":    StatusServerRenewProhibited,
+	"serverTransferProhibited": StatusServerTransferProhibited,
+	"serverUpdateProhibited":   StatusServerUpdateProhibited,
+	"transferPeriod":           StatusTransferPeriod,
+}
+
+// rdapStatuses is the reverse of eppStatuses.
+var rdapStatuses = func() map[Status]string {
+	m := make(map[Status]string, len(eppStatuses))
+	for code, status := range eppStatuses {
+		m[status] = code
+	}
+	return m
+}()
+
+// StatusFromEPP returns the RDAP status value for an EPP status code such as
+// "clientTransferProhibited". The code is matched case-insensitively.
+func StatusFromEPP(code string) (Status, bool) {
+	for eppCode, status := range eppStatuses {
+		if strings.EqualFold(eppCode, strings.TrimSpace(code)) {
+			return status, true
+		}
+	}
+	return "", false
+}
+
+// EPP returns the EPP status code for the status, e.g.
+// "clientTransferProhibited". Statuses without an EPP equivalent, such as
+// "validated", report false.
+func (s Status) EPP() (string, bool) {
+	code, ok := rdapStatuses[s.Normalize()]
+	return code, ok
+}
+
+// ParseStatus normalizes a status value as found in RDAP responses. Case,
+// underscores, hyphens and repeated whitespace are ignored, EPP codes are
+// mapped to their RDAP values, and a trailing URL (as in
+// "clientHold https://icann.org/epp#clientHold") is dropped.
+func ParseStatus(value string) Status {
+	fields := strings.Fields(value)
+	for i, field := range fields {
+		if strings.Contains(field, "://") {
+			fields = fields[:i]
+			break
+		}
+	}
+
+	if len(fields) == 1 {
+		if status, ok := StatusFromEPP(fields[0]); ok {
+			return status
+		}
+	}
+
+	var words []string
+	for _, field := range fields {
+		words = append(words, splitStatusWords(field)...)
+	}
+	return Status(strings.Join(words, " "))
+}
+
+// splitStatusWords splits a field on underscores, hyphens and lower to upper
+// case transitions, returning the words in lower case.
+func splitStatusWords(field string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+
+	var prev rune
+	for _, r := range field {
+		switch {
+		case r == '_' || r == '-':
+			flush()
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	flush()
+	return words
+}
+
+// hasStatus reports whether statuses contains any of want.
+func hasStatus(statuses []Status, want ...Status) bool {
+	for _, status := range statuses {
+		status = status.Normalize()
+		for _, w := range want {
+			if status == w {
+				return true
+			}
+		}
+	}
+	return false
+}
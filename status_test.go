@@ -0,0 +1,81 @@
+package openrdap
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Status
+	}{
+		{name: "RDAP value", input: "client transfer prohibited", expected: StatusClientTransferProhibited},
+		{name: "Mixed case and spacing", input: "  Client  Transfer Prohibited ", expected: StatusClientTransferProhibited},
+		{name: "EPP code", input: "clientTransferProhibited", expected: StatusClientTransferProhibited},
+		{name: "EPP code with URL", input: "serverHold https://icann.org/epp#serverHold", expected: StatusServerHold},
+		{name: "Underscores", input: "PENDING_DELETE", expected: StatusPendingDelete},
+		{name: "Hyphens", input: "redemption-period", expected: StatusRedemptionPeriod},
+		{name: "EPP ok", input: "ok", expected: StatusActive},
+		{name: "EPP linked", input: "linked", expected: StatusAssociated},
+		{name: "Unknown camel case", input: "someNewStatus", expected: "some new status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ParseStatus(tt.input); result != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestStatusEPP(t *testing.T) {
+	if code, ok := StatusClientTransferProhibited.EPP(); !ok || code != "clientTransferProhibited" {
+		t.Errorf("unexpected EPP code %q", code)
+	}
+	if code, ok := StatusActive.EPP(); !ok || code != "ok" {
+		t.Errorf("unexpected EPP code %q", code)
+	}
+	if _, ok := StatusValidated.EPP(); ok {
+		t.Error("expected no EPP code for validated")
+	}
+	if status, ok := StatusFromEPP("ServerUpdateProhibited"); !ok || status != StatusServerUpdateProhibited {
+		t.Errorf("unexpected status %q", status)
+	}
+}
+
+func TestDomainStatusHelpers(t *testing.T) {
+	fileData, err := os.ReadFile("test/example_domain_perihwk.json")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var domain Domain
+	if err := json.Unmarshal(fileData, &domain); err != nil {
+		t.Fatalf("failed to parse domain: %v", err)
+	}
+
+	if !domain.IsTransferLocked() {
+		t.Error("expected the domain to be transfer locked")
+	}
+	if !domain.HasStatus("clientTransferProhibited") {
+		t.Error("expected HasStatus to accept EPP codes")
+	}
+	if domain.IsOnHold() || domain.IsPendingDelete() || domain.IsInRedemption() {
+		t.Errorf("unexpected status %v", domain.Status)
+	}
+
+	if err := json.Unmarshal([]byte(`{"status": ["Client Hold", "pendingRestore"]}`), &domain); err != nil {
+		t.Fatalf("failed to parse domain: %v", err)
+	}
+	if !domain.IsOnHold() || !domain.IsInRedemption() || domain.IsTransferLocked() {
+		t.Errorf("unexpected status %v", domain.Status)
+	}
+	// the values of the response are kept
+	if domain.Status[1] != "pendingRestore" || domain.Status[1].Normalize() != StatusPendingRestore {
+		t.Errorf("unexpected status %v", domain.Status)
+	}
+}
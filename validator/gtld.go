@@ -0,0 +1,111 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/perihwk/openrdap"
+)
+
+// gtldConformance lists the identifiers gTLD responses must include.
+var gtldConformance = []string{
+	"icann_rdap_response_profile_0",
+	"icann_rdap_technical_implementation_guide_0",
+}
+
+// gtldNotices lists the notices gTLD domain responses must include.
+var gtldNotices = []string{
+	"Status Codes",
+	"RDDS Inaccuracy Complaint Form",
+}
+
+// gtldDomain runs the checks of the ICANN gTLD RDAP profile for a domain.
+func (v *validator) gtldDomain(domain *openrdap.Domain, path string) {
+	for _, id := range gtldConformance {
+		// later versions of the profile use higher suffixes
+		if !hasPrefixed(domain.Conformance, strings.TrimSuffix(id, "0")) {
+			v.add(SeverityError, CodeMissingConformanceID, path+".rdapConformance", "rdapConformance does not list %s", id)
+		}
+	}
+
+	for _, title := range gtldNotices {
+		found := false
+		for _, notice := range domain.Notices {
+			if strings.EqualFold(notice.Title, title) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.add(SeverityError, CodeMissingNotice, path+".notices", "no %q notice", title)
+		}
+	}
+
+	if openrdap.EventByAction(domain, openrdap.EventLastUpdateOfRDAPDatabase) == nil {
+		v.add(SeverityError, CodeMissingEvent, path+".events", "no %q event", openrdap.EventLastUpdateOfRDAPDatabase)
+	}
+	for _, action := range []string{openrdap.EventRegistration, openrdap.EventExpiration} {
+		if openrdap.EventByAction(domain, action) == nil {
+			v.add(SeverityWarning, CodeMissingEvent, path+".events", "no %q event", action)
+		}
+	}
+
+	registrarIndex := -1
+	for i := range domain.Entities {
+		if domain.Entities[i].HasRole("registrar") {
+			registrarIndex = i
+			break
+		}
+	}
+	if registrarIndex < 0 {
+		v.add(SeverityError, CodeMissingRegistrar, path+".entities", "no entity with the registrar role")
+		return
+	}
+	registrar := &domain.Entities[registrarIndex]
+	registrarPath := fmt.Sprintf("%s.entities[%d]", path, registrarIndex)
+
+	hasIANAID := false
+	for _, id := range registrar.PublicIDs {
+		if strings.EqualFold(id.Type, "IANA Registrar ID") && id.Identifier != "" {
+			hasIANAID = true
+		}
+	}
+	if !hasIANAID {
+		v.add(SeverityError, CodeMissingIANAID, registrarPath+".publicIds", "registrar has no \"IANA Registrar ID\" public ID")
+	}
+
+	abuseIndex := -1
+	for i := range registrar.Entities {
+		if registrar.Entities[i].HasRole("abuse") {
+			abuseIndex = i
+			break
+		}
+	}
+	if abuseIndex < 0 {
+		v.add(SeverityError, CodeMissingAbuseContact, registrarPath+".entities", "registrar has no entity with the abuse role")
+		return
+	}
+
+	abuse := &registrar.Entities[abuseIndex]
+	abusePath := fmt.Sprintf("%s.entities[%d].vcardArray", registrarPath, abuseIndex)
+	var vcard openrdap.VCard
+	if len(abuse.VCards) > 0 {
+		vcard = abuse.VCards[0]
+	}
+	if vcard.Email == "" {
+		v.add(SeverityError, CodeMissingAbuseEmail, abusePath, "abuse contact has no email")
+	}
+	if vcard.Telephone == "" {
+		v.add(SeverityError, CodeMissingAbusePhone, abusePath, "abuse contact has no telephone number")
+	}
+}
+
+// hasPrefixed reports whether any of values starts with prefix.
+func hasPrefixed(values []string, prefix string) bool {
+	for _, v := range values {
+		if strings.HasPrefix(v, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+// Package validator checks decoded RDAP responses against RFC 9083 and,
+// optionally, the ICANN gTLD RDAP profile.
+//
+// Findings carry a severity, a machine readable code and the JSON path of
+// the offending member, e.g. "$.entities[0].events[1].eventDate".
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/perihwk/openrdap"
+)
+
+// Severity ranks findings.
+type Severity int
+
+const (
+	// SeverityInfo marks deviations from common practice.
+	SeverityInfo Severity = iota
+	// SeverityWarning marks violations of a SHOULD of the specification.
+	SeverityWarning
+	// SeverityError marks violations of a MUST of the specification.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityInfo:
+		return "info"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
+
+// Finding codes reported by Validate.
+const (
+	CodeMissingConformance     = "missing-rdap-conformance"
+	CodeMissingConformanceID   = "missing-conformance-id"
+	CodeMissingObjectClassName = "missing-object-class-name"
+	CodeWrongObjectClassName   = "wrong-object-class-name"
+	CodeMissingEventAction     = "missing-event-action"
+	CodeInvalidEventDate       = "invalid-event-date"
+	CodeMissingEvent           = "missing-event"
+	CodeMissingLinkHref        = "missing-link-href"
+	CodeMissingSelfLink        = "missing-self-link"
+	CodeUnknownStatus          = "unknown-status"
+	CodeMissingRoles           = "missing-entity-roles"
+	CodeMissingLDHName         = "missing-ldh-name"
+	CodeMissingNotice          = "missing-notice"
+	CodeMissingRegistrar       = "missing-registrar"
+	CodeMissingIANAID          = "missing-registrar-iana-id"
+	CodeMissingAbuseContact    = "missing-abuse-contact"
+	CodeMissingAbuseEmail      = "missing-abuse-email"
+	CodeMissingAbusePhone      = "missing-abuse-phone"
+)
+
+// A Finding is a single problem found in a response.
+type Finding struct {
+	Severity Severity
+	// Code identifies the check, e.g. CodeInvalidEventDate.
+	Code string
+	// Path is the JSON path of the member the finding is about.
+	Path    string
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s %s %s: %s", f.Severity, f.Path, f.Code, f.Message)
+}
+
+// An Option configures Validate.
+type Option func(*validator)
+
+// WithGTLDProfile enables the checks of the ICANN gTLD RDAP profile for
+// domains: registrar with IANA ID, abuse contact, required events, notices
+// and conformance identifiers.
+//
+// https://www.icann.org/gtld-rdap-profile
+func WithGTLDProfile() Option {
+	return func(v *validator) {
+		v.gtldProfile = true
+	}
+}
+
+// HasErrors reports whether findings contain a finding of SeverityError.
+func HasErrors(findings []Finding) bool {
+	return MaxSeverity(findings) == SeverityError
+}
+
+// MaxSeverity returns the highest severity of findings, SeverityInfo if there
+// are none.
+func MaxSeverity(findings []Finding) Severity {
+	highest := SeverityInfo
+	for _, f := range findings {
+		if f.Severity > highest {
+			highest = f.Severity
+		}
+	}
+	return highest
+}
+
+type validator struct {
+	gtldProfile bool
+	findings    []Finding
+}
+
+func (v *validator) add(severity Severity, code, path, format string, args ...any) {
+	v.findings = append(v.findings, Finding{
+		Severity: severity,
+		Code:     code,
+		Path:     path,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate checks obj, a topmost *openrdap.Domain, *openrdap.Entity,
+// *openrdap.Nameserver, *openrdap.IPNetwork or *openrdap.Autnum, and returns
+// the findings in the order of the response.
+func Validate(obj openrdap.Object, opts ...Option) []Finding {
+	v := &validator{}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	const root = "$"
+	switch obj := obj.(type) {
+	case *openrdap.Domain:
+		v.conformance(obj.Conformance, root)
+		v.object(obj, "domain", root)
+		if obj.LDHName == "" && obj.UnicodeName == "" {
+			v.add(SeverityWarning, CodeMissingLDHName, root+".ldhName", "domain has neither ldhName nor unicodeName")
+		}
+		for i := range obj.Nameservers {
+			v.object(&obj.Nameservers[i], "nameserver", fmt.Sprintf("%s.nameservers[%d]", root, i))
+		}
+		if obj.Network != nil {
+			v.object(obj.Network, "ip network", root+".network")
+		}
+		if v.gtldProfile {
+			v.gtldDomain(obj, root)
+		}
+	case *openrdap.Entity:
+		v.conformance(obj.Conformance, root)
+		v.object(obj, "entity", root)
+	case *openrdap.Nameserver:
+		v.conformance(obj.Conformance, root)
+		v.object(obj, "nameserver", root)
+	case *openrdap.IPNetwork:
+		v.conformance(obj.Conformance, root)
+		v.object(obj, "ip network", root)
+	case *openrdap.Autnum:
+		v.conformance(obj.Conformance, root)
+		v.object(obj, "autnum", root)
+	default:
+		v.add(SeverityError, CodeWrongObjectClassName, root+".objectClassName", "unsupported object %T", obj)
+	}
+
+	if len(obj.GetLinks()) > 0 || v.gtldProfile {
+		if openrdap.LinkByRel(obj, "self") == nil {
+			severity := SeverityWarning
+			if v.gtldProfile {
+				severity = SeverityError
+			}
+			v.add(severity, CodeMissingSelfLink, root+".links", "object has no self link")
+		}
+	}
+	return v.findings
+}
+
+// conformance checks the rdapConformance member of a topmost object.
+func (v *validator) conformance(conformance []string, path string) {
+	path += ".rdapConformance"
+	if len(conformance) == 0 {
+		v.add(SeverityError, CodeMissingConformance, path, "topmost object has no rdapConformance")
+		return
+	}
+	if !contains(conformance, "rdap_level_0") {
+		v.add(SeverityError, CodeMissingConformanceID, path, "rdapConformance does not list rdap_level_0")
+	}
+}
+
+// object checks the members shared by all object classes, and the entities
+// of obj recursively.
+func (v *validator) object(obj openrdap.Object, class, path string) {
+	switch name := obj.GetObjectClassName(); {
+	case name == "":
+		v.add(SeverityError, CodeMissingObjectClassName, path+".objectClassName", "missing objectClassName, expected %q", class)
+	case name != class:
+		v.add(SeverityError, CodeWrongObjectClassName, path+".objectClassName", "objectClassName is %q, expected %q", name, class)
+	}
+
+	for i, event := range obj.GetEvents() {
+		v.event(event, fmt.Sprintf("%s.events[%d]", path, i))
+	}
+	for i, link := range obj.GetLinks() {
+		if link.Href == "" {
+			v.add(SeverityError, CodeMissingLinkHref, fmt.Sprintf("%s.links[%d].href", path, i), "link %q has no href", link.Rel)
+		}
+	}
+	for i, status := range obj.GetStatus() {
+		statusPath := fmt.Sprintf("%s.status[%d]", path, i)
+		switch {
+		case !status.Registered():
+			v.add(SeverityWarning, CodeUnknownStatus, statusPath, "status %q is not registered with IANA", status)
+		case status.Normalize() != status:
+			// e.g. an EPP code or a variant in case or spacing
+			v.add(SeverityWarning, CodeUnknownStatus, statusPath, "status %q is not an RDAP status value, expected %q", status, status.Normalize())
+		}
+	}
+
+	entities := obj.GetEntities()
+	for i := range entities {
+		entityPath := fmt.Sprintf("%s.entities[%d]", path, i)
+		if len(entities[i].Roles) == 0 {
+			v.add(SeverityWarning, CodeMissingRoles, entityPath+".roles", "embedded entity has no roles")
+		}
+		v.object(&entities[i], "entity", entityPath)
+	}
+}
+
+func (v *validator) event(event openrdap.Event, path string) {
+	if event.Action == "" {
+		v.add(SeverityError, CodeMissingEventAction, path+".eventAction", "event has no eventAction")
+	}
+	if event.Date == "" {
+		v.add(SeverityError, CodeInvalidEventDate, path+".eventDate", "event %q has no eventDate", event.Action)
+		return
+	}
+	// event.Time is parsed leniently, the response must use RFC 3339, which
+	// allows a lower case "t" and "z"
+	if _, err := time.Parse(time.RFC3339, strings.ToUpper(event.Date)); err != nil {
+		v.add(SeverityError, CodeInvalidEventDate, path+".eventDate", "eventDate %q is not an RFC 3339 date-time", event.Date)
+	}
+}
+
+func contains(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package validator
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/perihwk/openrdap"
+)
+
+func TestValidateCompliantDomain(t *testing.T) {
+	fileData, err := os.ReadFile("../test/example_domain_perihwk.json")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	obj, err := openrdap.Decode(fileData)
+	if err != nil {
+		t.Fatalf("failed to decode: %v", err)
+	}
+
+	for _, f := range Validate(obj, WithGTLDProfile()) {
+		if f.Severity == SeverityError {
+			t.Errorf("unexpected finding %s", f)
+		}
+	}
+}
+
+func TestValidateFindings(t *testing.T) {
+	var domain openrdap.Domain
+	err := json.Unmarshal([]byte(`{
+  "objectClassName": "Domain",
+  "ldhName": "example.com",
+  "status": ["active", "made up", "clientTransferProhibited", "Active"],
+  "events": [
+    {"eventAction": "registration", "eventDate": "2020-01-01T00:00:00Z"},
+    {"eventAction": "expiration", "eventDate": "01/01/2030"},
+    {"eventAction": "last changed", "eventDate": "2024-01-05 15:38:38"},
+    {"eventAction": "reregistration", "eventDate": "2024-01-05t15:38:38z"}
+  ],
+  "links": [{"rel": "related", "href": "https://rdap.example/registrar"}],
+  "entities": [
+    {
+      "objectClassName": "entity",
+      "roles": ["registrar"],
+      "entities": [{"objectClassName": "entity", "roles": ["abuse"],
+        "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["email", {}, "text", "abuse@example.com"]]]}]
+    },
+    {"handle": "TECH-1"}
+  ]
+}`), &domain)
+	if err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	type result struct {
+		Severity Severity
+		Code     string
+		Path     string
+	}
+	var results []result
+	for _, f := range Validate(&domain, WithGTLDProfile()) {
+		results = append(results, result{f.Severity, f.Code, f.Path})
+	}
+
+	expected := []result{
+		{SeverityError, CodeMissingConformance, "$.rdapConformance"},
+		{SeverityError, CodeWrongObjectClassName, "$.objectClassName"},
+		{SeverityError, CodeInvalidEventDate, "$.events[1].eventDate"},
+		{SeverityError, CodeInvalidEventDate, "$.events[2].eventDate"},
+		{SeverityWarning, CodeUnknownStatus, "$.status[1]"},
+		{SeverityWarning, CodeUnknownStatus, "$.status[2]"},
+		{SeverityWarning, CodeUnknownStatus, "$.status[3]"},
+		{SeverityWarning, CodeMissingRoles, "$.entities[1].roles"},
+		{SeverityError, CodeMissingObjectClassName, "$.entities[1].objectClassName"},
+		{SeverityError, CodeMissingConformanceID, "$.rdapConformance"},
+		{SeverityError, CodeMissingConformanceID, "$.rdapConformance"},
+		{SeverityError, CodeMissingNotice, "$.notices"},
+		{SeverityError, CodeMissingNotice, "$.notices"},
+		{SeverityError, CodeMissingEvent, "$.events"},
+		{SeverityError, CodeMissingIANAID, "$.entities[0].publicIds"},
+		{SeverityError, CodeMissingAbusePhone, "$.entities[0].entities[0].vcardArray"},
+		{SeverityError, CodeMissingSelfLink, "$.links"},
+	}
+	if !cmp.Equal(results, expected) {
+		t.Errorf("unexpected findings %s", cmp.Diff(expected, results))
+	}
+	if !HasErrors(Validate(&domain)) {
+		t.Error("expected errors without the gTLD profile")
+	}
+}
+
+func TestValidateIPNetwork(t *testing.T) {
+	fileData, err := os.ReadFile("../test/example_ip_8888.json")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var network openrdap.IPNetwork
+	if err := json.Unmarshal(fileData, &network); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	if findings := Validate(&network); HasErrors(findings) {
+		t.Errorf("unexpected findings %v", findings)
+	}
+}
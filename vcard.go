@@ -2,6 +2,8 @@ package openrdap
 
 import (
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
 )
 
@@ -26,13 +28,38 @@ import (
 //	]
 
 type VCard struct {
-	Version   string
-	FullName  string
+	Version  string
+	FullName string
+	Kind     string
+
+	// Address, Email, Telephone and Org hold the preferred value of the
+	// respective property, i.e. the one with the lowest "pref" parameter, or
+	// the first one if none is marked as preferred. All values are kept in
+	// Addresses, Emails, Telephones and Orgs.
+	//
+	// The components of an "org" value, the organization name followed by
+	// any units, are joined with ";" and kept in Value.Components.
 	Address   Address
-	Kind      string
 	Email     string
 	Telephone string
 	Org       string
+
+	Name        *Name
+	Addresses   []Address
+	Emails      []Value
+	Telephones  []Value
+	Orgs        []Value
+	Titles      []Value
+	Roles       []Value
+	URLs        []Value
+	ContactURIs []Value
+	Geos        []Value
+	Langs       []Value
+	Keys        []Value
+
+	// Extra holds the properties which are not modeled above, e.g. "bday" or
+	// "tz", in their raw form.
+	Extra []Property
 }
 
 type Address struct {
@@ -44,6 +71,100 @@ type Address struct {
 	Region          string // state or province
 	PostalCode      string
 	Country         string
+
+	Params Params
+}
+
+// Name is the structured name of the "n" property. Each component may hold
+// several values, e.g. multiple honorific suffixes.
+//
+// https://datatracker.ietf.org/doc/html/rfc6350#section-6.2.2
+type Name struct {
+	FamilyNames     []string
+	GivenNames      []string
+	AdditionalNames []string
+	Prefixes        []string
+	Suffixes        []string
+
+	Params Params
+}
+
+// Value is a property with a single value, such as an email address or a
+// telephone number.
+type Value struct {
+	Value string
+	// Components holds the components of a structured value, e.g. the
+	// organization name and units of "org", whose Value is the components
+	// joined with ";". It is nil for other values.
+	Components []string
+
+	// ValueType is the jCard value type, e.g. "text" or "uri".
+	ValueType string
+	Params    Params
+}
+
+// Property is a jCard property kept in its raw form.
+type Property struct {
+	Name      string
+	Params    Params
+	ValueType string
+	Values    []interface{}
+}
+
+// Params holds the parameters of a jCard property. Parameter names are lower
+// case, and single values are stored as a slice of one.
+//
+// https://datatracker.ietf.org/doc/html/rfc6350#section-5
+type Params map[string][]string
+
+// Types returns the values of the "type" parameter, e.g. "work" and "voice".
+func (p Params) Types() []string {
+	return p["type"]
+}
+
+// HasType reports whether the "type" parameter contains typ.
+func (p Params) HasType(typ string) bool {
+	for _, t := range p["type"] {
+		if strings.EqualFold(t, typ) {
+			return true
+		}
+	}
+	return false
+}
+
+// Pref returns the value of the "pref" parameter, from 1 (most preferred) to
+// 100, or 0 if it is missing or invalid.
+func (p Params) Pref() int {
+	if len(p["pref"]) == 0 {
+		return 0
+	}
+	pref, err := strconv.Atoi(p["pref"][0])
+	if err != nil || pref < 1 || pref > 100 {
+		return 0
+	}
+	return pref
+}
+
+// TelephonesByType returns the telephone numbers with the given type, e.g.
+// "fax". Numbers without a type are voice numbers.
+func (v *VCard) TelephonesByType(typ string) []Value {
+	var result []Value
+	for _, tel := range v.Telephones {
+		if tel.Params.HasType(typ) || (len(tel.Params.Types()) == 0 && strings.EqualFold(typ, "voice")) {
+			result = append(result, tel)
+		}
+	}
+	return result
+}
+
+// Voice returns the preferred voice telephone number, or "".
+func (v *VCard) Voice() string {
+	return preferredValue(v.TelephonesByType("voice")).Value
+}
+
+// Fax returns the preferred fax number, or "".
+func (v *VCard) Fax() string {
+	return preferredValue(v.TelephonesByType("fax")).Value
 }
 
 // ParseJCard parses a jCard from its JSON representation
@@ -54,7 +175,7 @@ func parseJCard(jcardData []interface{}) (VCard, error) {
 		return jcard, nil
 	}
 
-	if jcardData[0] != "vcard" {
+	if len(jcardData) < 2 || jcardData[0] != "vcard" {
 		return jcard, fmt.Errorf("not a vcard")
 	}
 
@@ -72,54 +193,248 @@ func parseJCard(jcardData []interface{}) (VCard, error) {
 		}
 
 		// Parse the jCard field based on the property type (first element)
-		propertyName := propArray[0].(string)
+		propertyName, ok := propArray[0].(string)
+		if !ok {
+			continue
+		}
+		params := parseParams(propArray[1])
+		valueType, _ := propArray[2].(string)
 		propertyValue := propArray[3]
 
-		switch propertyName {
-		case "version":
-			if version, ok := propertyValue.(string); ok {
-				jcard.Version = version
-			}
-		case "fn":
-			if fn, ok := propertyValue.(string); ok {
-				jcard.FullName = fn
-			}
-		// adr property is parsed according to the following specification
-		// https://datatracker.ietf.org/doc/html/rfc6350#section-6.3.1
-		case "adr":
-			if propMap, ok := propArray[1].(map[string]interface{}); ok {
-				if label, ok := propMap["label"].(string); ok {
-					parsedAddr, err := parseAddressFromLabel(label)
-					if err == nil {
-						jcard.Address = *parsedAddr
-					}
-					jcard.Address.Label = label
-				}
-			}
+		// properties with several values are not modeled, and are kept in
+		// Extra only, so they are encoded once
+		if len(propArray) > 4 || !jcard.parseProperty(strings.ToLower(propertyName), params, valueType, propertyValue) {
+			jcard.Extra = append(jcard.Extra, Property{
+				Name:      propertyName,
+				Params:    params,
+				ValueType: valueType,
+				Values:    propArray[3:],
+			})
+		}
+	}
 
-			if addr := parseStructuredAddress(propArray); addr != nil {
-				jcard.Address = *addr
-			}
-		case "kind":
-			if kind, ok := propertyValue.(string); ok {
-				jcard.Kind = kind
+	jcard.Address = preferredAddress(jcard.Addresses)
+	jcard.Email = preferredValue(jcard.Emails).Value
+	jcard.Telephone = preferredValue(jcard.Telephones).Value
+	jcard.Org = preferredValue(jcard.Orgs).Value
+
+	return jcard, nil
+}
+
+// parseProperty stores a property in its typed field, reporting whether the
+// property is modeled by VCard.
+func (v *VCard) parseProperty(name string, params Params, valueType string, value interface{}) bool {
+	var values *[]Value
+	switch name {
+	case "version":
+		v.Version, _ = value.(string)
+		return true
+	case "fn":
+		v.FullName, _ = value.(string)
+		return true
+	case "kind":
+		v.Kind, _ = value.(string)
+		return true
+	case "n":
+		if v.Name != nil {
+			return false
+		}
+		v.Name = parseName(value)
+		if v.Name == nil {
+			return false
+		}
+		v.Name.Params = params
+		return true
+	// adr property is parsed according to the following specification
+	// https://datatracker.ietf.org/doc/html/rfc6350#section-6.3.1
+	case "adr":
+		addr := parseAddress(params, value)
+		if addr == nil {
+			return false
+		}
+		v.Addresses = append(v.Addresses, *addr)
+		return true
+	case "email":
+		values = &v.Emails
+	case "tel":
+		values = &v.Telephones
+	case "org":
+		text, ok := textValue(value)
+		if !ok {
+			return false
+		}
+		org := Value{Value: text, ValueType: valueType, Params: params}
+		if _, structured := value.([]interface{}); structured {
+			org.Components, _ = componentValues(value)
+		}
+		v.Orgs = append(v.Orgs, org)
+		return true
+	case "title":
+		values = &v.Titles
+	case "role":
+		values = &v.Roles
+	case "url":
+		values = &v.URLs
+	case "contact-uri":
+		values = &v.ContactURIs
+	case "geo":
+		values = &v.Geos
+	case "lang":
+		values = &v.Langs
+	case "key":
+		values = &v.Keys
+	default:
+		return false
+	}
+
+	text, ok := textValue(value)
+	if !ok {
+		return false
+	}
+	*values = append(*values, Value{Value: text, ValueType: valueType, Params: params})
+	return true
+}
+
+// parseParams converts the parameter object of a jCard property.
+func parseParams(data interface{}) Params {
+	paramMap, ok := data.(map[string]interface{})
+	if !ok || len(paramMap) == 0 {
+		return nil
+	}
+
+	params := make(Params, len(paramMap))
+	for name, value := range paramMap {
+		name = strings.ToLower(name)
+		switch value := value.(type) {
+		case []interface{}:
+			for _, v := range value {
+				if str, ok := textValue(v); ok {
+					params[name] = append(params[name], str)
+				}
 			}
-		case "email":
-			if email, ok := propertyValue.(string); ok {
-				jcard.Email = email
+		default:
+			if str, ok := textValue(value); ok {
+				params[name] = append(params[name], str)
 			}
-		case "tel":
-			if telephone, ok := propertyValue.(string); ok {
-				jcard.Telephone = telephone
+		}
+	}
+	return params
+}
+
+// textValue converts a jCard value to a string. Structured values are joined
+// with ";" as in the vCard text format.
+func textValue(value interface{}) (string, bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(value), true
+	case []interface{}:
+		parts := make([]string, 0, len(value))
+		for _, v := range value {
+			str, ok := v.(string)
+			if !ok {
+				return "", false
 			}
-		case "org":
-			if org, ok := propertyValue.(string); ok {
-				jcard.Org = org
+			parts = append(parts, str)
+		}
+		return strings.Join(parts, ";"), true
+	}
+	return "", false
+}
+
+// componentValues converts a component of a structured value, which is
+// either a string or an array of strings.
+func componentValues(value interface{}) ([]string, bool) {
+	switch value := value.(type) {
+	case string:
+		if value == "" {
+			return nil, true
+		}
+		return []string{value}, true
+	case []interface{}:
+		var result []string
+		for _, v := range value {
+			str, ok := v.(string)
+			if !ok {
+				return nil, false
 			}
+			result = append(result, str)
 		}
+		return result, true
 	}
+	return nil, false
+}
 
-	return jcard, nil
+// parseName parses the structured value of the "n" property.
+func parseName(value interface{}) *Name {
+	components, ok := value.([]interface{})
+	if !ok || len(components) > 5 {
+		return nil
+	}
+
+	name := &Name{}
+	fields := []*[]string{&name.FamilyNames, &name.GivenNames, &name.AdditionalNames, &name.Prefixes, &name.Suffixes}
+	for i, component := range components {
+		values, ok := componentValues(component)
+		if !ok {
+			return nil
+		}
+		*fields[i] = values
+	}
+	return name
+}
+
+// parseAddress parses an "adr" property. The structured value is used if it
+// has any non-empty component, the label parameter otherwise.
+func parseAddress(params Params, value interface{}) *Address {
+	addr := parseStructuredAddress(value)
+	if addr == nil {
+		if _, ok := value.([]interface{}); !ok {
+			return nil
+		}
+		addr = &Address{}
+		if label := params["label"]; len(label) > 0 {
+			addr, _ = parseAddressFromLabel(label[0])
+		}
+	}
+
+	if label := params["label"]; len(label) > 0 {
+		addr.Label = label[0]
+	}
+	addr.Params = params
+	return addr
+}
+
+// preferredValue returns the value with the lowest pref parameter, or the
+// first value if none has one.
+func preferredValue(values []Value) Value {
+	if i := preferredIndex(len(values), func(i int) Params { return values[i].Params }); i >= 0 {
+		return values[i]
+	}
+	return Value{}
+}
+
+// preferredAddress returns the address with the lowest pref parameter, or the
+// first address if none has one.
+func preferredAddress(addresses []Address) Address {
+	if i := preferredIndex(len(addresses), func(i int) Params { return addresses[i].Params }); i >= 0 {
+		return addresses[i]
+	}
+	return Address{}
+}
+
+func preferredIndex(n int, params func(int) Params) int {
+	best, bestPref := -1, 0
+	for i := 0; i < n; i++ {
+		pref := params(i).Pref()
+		if best < 0 || (pref != 0 && (bestPref == 0 || pref < bestPref)) {
+			best, bestPref = i, pref
+		}
+	}
+	return best
 }
 
 // attempts to parse the address from the label string using its position
@@ -167,32 +482,36 @@ func parseAddressFromLabel(label string) (*Address, error) {
 	return addr, nil
 }
 
-// parseStructuredAddress parses a structured address if available.
-func parseStructuredAddress(propArray []interface{}) *Address {
-	if structuredAddress, ok := propArray[3].([]interface{}); ok {
+// parseStructuredAddress parses a structured address if available. Components
+// with several values, e.g. multiple street lines, are joined with newlines.
+func parseStructuredAddress(value interface{}) *Address {
+	if structuredAddress, ok := value.([]interface{}); ok {
 		addr := &Address{}
 		emptyAddr := true // track if all the fields are ""
 		for i, v := range structuredAddress {
-			if str, ok := v.(string); ok {
-				switch i {
-				case 0:
-					addr.PostOfficeBox = str
-				case 1:
-					addr.ExtendedAddress = str
-				case 2:
-					addr.StreetAddress = str
-				case 3:
-					addr.Locality = str
-				case 4:
-					addr.Region = str
-				case 5:
-					addr.PostalCode = str
-				case 6:
-					addr.Country = str
-				}
-				if str != "" {
-					emptyAddr = false
-				}
+			values, ok := componentValues(v)
+			if !ok {
+				continue
+			}
+			str := strings.Join(values, "\n")
+			switch i {
+			case 0:
+				addr.PostOfficeBox = str
+			case 1:
+				addr.ExtendedAddress = str
+			case 2:
+				addr.StreetAddress = str
+			case 3:
+				addr.Locality = str
+			case 4:
+				addr.Region = str
+			case 5:
+				addr.PostalCode = str
+			case 6:
+				addr.Country = str
+			}
+			if str != "" {
+				emptyAddr = false
 			}
 		}
 
@@ -203,3 +522,177 @@ func parseStructuredAddress(propArray []interface{}) *Address {
 	}
 	return nil
 }
+
+// encodeJCard encodes a vCard in its jCard JSON representation. The singular
+// Address, Email, Telephone and Org fields are only encoded if the respective
+// list is empty.
+func encodeJCard(v VCard) []interface{} {
+	version := v.Version
+	if version == "" {
+		version = "4.0"
+	}
+
+	properties := []interface{}{
+		[]interface{}{"version", map[string]interface{}{}, "text", version},
+	}
+	if v.FullName != "" {
+		properties = append(properties, []interface{}{"fn", map[string]interface{}{}, "text", v.FullName})
+	}
+	if v.Kind != "" {
+		properties = append(properties, []interface{}{"kind", map[string]interface{}{}, "text", v.Kind})
+	}
+	if v.Name != nil {
+		properties = append(properties, []interface{}{"n", encodeParams(v.Name.Params), "text", encodeName(v.Name)})
+	}
+
+	addresses := v.Addresses
+	if len(addresses) == 0 && !reflect.DeepEqual(v.Address, Address{}) {
+		addresses = []Address{v.Address}
+	}
+	for _, addr := range addresses {
+		properties = append(properties, encodeAddress(addr))
+	}
+
+	singular := func(value string, valueType string) []Value {
+		if value == "" {
+			return nil
+		}
+		return []Value{{Value: value, ValueType: valueType}}
+	}
+	telephoneType := "text"
+	if strings.HasPrefix(v.Telephone, "tel:") {
+		telephoneType = "uri"
+	}
+
+	for _, group := range []struct {
+		name     string
+		values   []Value
+		fallback []Value
+	}{
+		{"email", v.Emails, singular(v.Email, "text")},
+		{"tel", v.Telephones, singular(v.Telephone, telephoneType)},
+		{"org", v.Orgs, singular(v.Org, "text")},
+		{"title", v.Titles, nil},
+		{"role", v.Roles, nil},
+		{"url", v.URLs, nil},
+		{"contact-uri", v.ContactURIs, nil},
+		{"geo", v.Geos, nil},
+		{"lang", v.Langs, nil},
+		{"key", v.Keys, nil},
+	} {
+		values := group.values
+		if len(values) == 0 {
+			values = group.fallback
+		}
+		for _, value := range values {
+			valueType := value.ValueType
+			if valueType == "" {
+				valueType = "text"
+			}
+			var encoded interface{} = value.Value
+			if len(value.Components) > 0 && strings.Join(value.Components, ";") == value.Value {
+				encoded = stringsToInterfaces(value.Components)
+			}
+			properties = append(properties, []interface{}{group.name, encodeParams(value.Params), valueType, encoded})
+		}
+	}
+
+	for _, prop := range v.Extra {
+		encoded := []interface{}{prop.Name, encodeParams(prop.Params), prop.ValueType}
+		properties = append(properties, append(encoded, prop.Values...))
+	}
+
+	return []interface{}{"vcard", properties}
+}
+
+// encodeParams encodes property parameters, using a plain string for
+// parameters with a single value.
+func encodeParams(params Params) map[string]interface{} {
+	encoded := make(map[string]interface{}, len(params))
+	for name, values := range params {
+		if len(values) == 1 {
+			encoded[name] = values[0]
+		} else {
+			encoded[name] = stringsToInterfaces(values)
+		}
+	}
+	return encoded
+}
+
+// encodeComponent encodes a component of a structured value.
+func encodeComponent(values []string) interface{} {
+	switch len(values) {
+	case 0:
+		return ""
+	case 1:
+		return values[0]
+	}
+	return stringsToInterfaces(values)
+}
+
+func encodeName(name *Name) []interface{} {
+	return []interface{}{
+		encodeComponent(name.FamilyNames),
+		encodeComponent(name.GivenNames),
+		encodeComponent(name.AdditionalNames),
+		encodeComponent(name.Prefixes),
+		encodeComponent(name.Suffixes),
+	}
+}
+
+// encodeAddress encodes an "adr" property. Components which were derived
+// from the label are not repeated in the structured value.
+func encodeAddress(addr Address) []interface{} {
+	params := addr.Params
+	if addr.Label != "" && len(params["label"]) == 0 {
+		params = make(Params, len(addr.Params)+1)
+		for name, values := range addr.Params {
+			params[name] = values
+		}
+		params["label"] = []string{addr.Label}
+	}
+
+	structured := addr
+	if addr.Label != "" {
+		if fromLabel, err := parseAddressFromLabel(addr.Label); err == nil {
+			fromLabel.Label, fromLabel.Params = addr.Label, addr.Params
+			if reflect.DeepEqual(*fromLabel, addr) {
+				structured = Address{}
+			}
+		}
+	}
+
+	components := []string{
+		structured.PostOfficeBox,
+		structured.ExtendedAddress,
+		structured.StreetAddress,
+		structured.Locality,
+		structured.Region,
+		structured.PostalCode,
+		structured.Country,
+	}
+	value := make([]interface{}, len(components))
+	for i, component := range components {
+		if component == "" {
+			value[i] = ""
+			continue
+		}
+		value[i] = encodeComponent(strings.Split(component, "\n"))
+	}
+
+	return []interface{}{"adr", encodeParams(params), "text", value}
+}
+
+func stringsToInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, v := range values {
+		result[i] = v
+	}
+	return result
+}
+
+// isZero reports whether the vCard holds no data, as for entities without a
+// vcardArray.
+func (v *VCard) isZero() bool {
+	return reflect.DeepEqual(*v, VCard{})
+}
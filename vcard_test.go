@@ -15,53 +15,88 @@ type testData struct {
 	ExpectedError  bool
 }
 
+// simonVCard is the vCard of the RFC 7095 example, with the given address.
+func simonVCard(address Address) VCard {
+	work := Params{"type": {"work"}}
+	voice := Value{
+		Value:     "tel:+1-418-656-9254;ext=102",
+		ValueType: "uri",
+		Params:    Params{"type": {"work", "voice"}, "pref": {"1"}},
+	}
+
+	return VCard{
+		Version:   "4.0",
+		FullName:  "Simon Perreault",
+		Kind:      "",
+		Address:   address,
+		Email:     "simon.perreault@viagenie.ca",
+		Telephone: "tel:+1-418-656-9254;ext=102",
+		Org:       "Viagenie",
+		Name: &Name{
+			FamilyNames: []string{"Perreault"},
+			GivenNames:  []string{"Simon"},
+			Suffixes:    []string{"ing. jr", "M.Sc."},
+		},
+		Addresses: []Address{address},
+		Emails:    []Value{{Value: "simon.perreault@viagenie.ca", ValueType: "text", Params: work}},
+		Telephones: []Value{
+			voice,
+			{
+				Value:     "tel:+1-418-262-6501",
+				ValueType: "uri",
+				Params:    Params{"type": {"work", "cell", "voice", "video", "text"}},
+			},
+		},
+		Orgs: []Value{{Value: "Viagenie", ValueType: "text", Params: work}},
+		URLs: []Value{{Value: "http://nomis80.org", ValueType: "uri", Params: Params{"type": {"home"}}}},
+		Geos: []Value{{Value: "geo:46.772673,-71.282945", ValueType: "uri", Params: work}},
+		Langs: []Value{
+			{Value: "fr", ValueType: "language-tag", Params: Params{"pref": {"1"}}},
+			{Value: "en", ValueType: "language-tag", Params: Params{"pref": {"2"}}},
+		},
+		Keys: []Value{{Value: "http://www.viagenie.ca/simon.perreault/simon.asc", ValueType: "uri", Params: work}},
+		Extra: []Property{
+			{Name: "bday", ValueType: "date-and-or-time", Values: []interface{}{"2013-02-14T12:30:00"}},
+			{Name: "anniversary", ValueType: "date-and-or-time", Values: []interface{}{"2009-08-08T14:30:00-05:00"}},
+			{Name: "gender", ValueType: "text", Values: []interface{}{"M"}},
+			{Name: "tz", ValueType: "utc-offset", Values: []interface{}{"-05:00"}},
+		},
+	}
+}
+
 func TestParseJCard(t *testing.T) {
 	// load sample data from file
 	sampleData := []testData{
 		{
 			Name:           "TestSuccessNoLabel",
 			SampleDataPath: "test/jcard/example.json",
-			ExpectedVCard: VCard{
-				Version:  "4.0",
-				FullName: "Simon Perreault",
-				Address: Address{
-					Label:           "",
-					PostOfficeBox:   "",
-					ExtendedAddress: "Suite D2-630",
-					StreetAddress:   "2875 Laurier",
-					Locality:        "Quebec",
-					Region:          "QC",
-					PostalCode:      "G1V 2M2",
-					Country:         "Canada",
-				},
-				Kind:      "",
-				Email:     "simon.perreault@viagenie.ca",
-				Telephone: "tel:+1-418-262-6501",
-				Org:       "Viagenie",
-			},
+			ExpectedVCard: simonVCard(Address{
+				Label:           "",
+				PostOfficeBox:   "",
+				ExtendedAddress: "Suite D2-630",
+				StreetAddress:   "2875 Laurier",
+				Locality:        "Quebec",
+				Region:          "QC",
+				PostalCode:      "G1V 2M2",
+				Country:         "Canada",
+				Params:          Params{"type": {"work"}},
+			}),
 			ExpectedError: false,
 		},
 		{
 			Name:           "TestSuccessWithLabel",
 			SampleDataPath: "test/jcard/example_label.json",
-			ExpectedVCard: VCard{
-				Version:  "4.0",
-				FullName: "Simon Perreault",
-				Address: Address{
-					Label:           "123 Maple Ave\nSuite 901\nVancouver\nBC\nA1B 2C9\nCanada",
-					PostOfficeBox:   "",
-					ExtendedAddress: "Suite 901",
-					StreetAddress:   "123 Maple Ave",
-					Locality:        "Vancouver",
-					Region:          "BC",
-					PostalCode:      "A1B 2C9",
-					Country:         "Canada",
-				},
-				Kind:      "",
-				Email:     "simon.perreault@viagenie.ca",
-				Telephone: "tel:+1-418-262-6501",
-				Org:       "Viagenie",
-			},
+			ExpectedVCard: simonVCard(Address{
+				Label:           "123 Maple Ave\nSuite 901\nVancouver\nBC\nA1B 2C9\nCanada",
+				PostOfficeBox:   "",
+				ExtendedAddress: "Suite 901",
+				StreetAddress:   "123 Maple Ave",
+				Locality:        "Vancouver",
+				Region:          "BC",
+				PostalCode:      "A1B 2C9",
+				Country:         "Canada",
+				Params:          Params{"label": {"123 Maple Ave\nSuite 901\nVancouver\nBC\nA1B 2C9\nCanada"}},
+			}),
 			ExpectedError: false,
 		},
 	}
@@ -89,3 +124,76 @@ func TestParseJCard(t *testing.T) {
 		}
 	}
 }
+
+func TestVCardTelephones(t *testing.T) {
+	jcardData := []interface{}{"vcard", []interface{}{
+		[]interface{}{"version", map[string]interface{}{}, "text", "4.0"},
+		[]interface{}{"tel", map[string]interface{}{"type": "fax"}, "uri", "tel:+1-555-555-0100"},
+		[]interface{}{"tel", map[string]interface{}{"type": []interface{}{"work", "voice"}}, "uri", "tel:+1-555-555-0101"},
+		[]interface{}{"tel", map[string]interface{}{}, "text", "+1 555 555 0102"},
+	}}
+
+	vcard, err := parseJCard(jcardData)
+	if err != nil {
+		t.Fatalf("Failed to parse jCard: %v", err)
+	}
+
+	if vcard.Fax() != "tel:+1-555-555-0100" {
+		t.Errorf("unexpected fax number %q", vcard.Fax())
+	}
+	if vcard.Voice() != "tel:+1-555-555-0101" {
+		t.Errorf("unexpected voice number %q", vcard.Voice())
+	}
+	if n := len(vcard.TelephonesByType("voice")); n != 2 {
+		t.Errorf("expected 2 voice numbers, got %d", n)
+	}
+}
+
+func TestVCardOrgComponents(t *testing.T) {
+	properties := []interface{}{
+		[]interface{}{"version", map[string]interface{}{}, "text", "4.0"},
+		[]interface{}{"org", map[string]interface{}{}, "text", "Example; Inc."},
+		[]interface{}{"org", map[string]interface{}{}, "text", []interface{}{"ABC, Inc.", "North American Division", "Marketing"}},
+	}
+
+	vcard, err := parseJCard([]interface{}{"vcard", properties})
+	if err != nil {
+		t.Fatalf("Failed to parse jCard: %v", err)
+	}
+
+	if vcard.Org != "Example; Inc." || vcard.Orgs[0].Components != nil {
+		t.Errorf("unexpected org %+v", vcard.Orgs[0])
+	}
+	if vcard.Orgs[1].Value != "ABC, Inc.;North American Division;Marketing" {
+		t.Errorf("unexpected org %q", vcard.Orgs[1].Value)
+	}
+	expected := []string{"ABC, Inc.", "North American Division", "Marketing"}
+	if diff := cmp.Diff(expected, vcard.Orgs[1].Components); diff != "" {
+		t.Errorf("unexpected components (-want +got):\n%s", diff)
+	}
+
+	// the components survive encoding
+	if diff := cmp.Diff([]interface{}{"vcard", properties}, encodeJCard(vcard)); diff != "" {
+		t.Errorf("unexpected jCard (-want +got):\n%s", diff)
+	}
+}
+
+func TestVCardMultiValuedProperty(t *testing.T) {
+	properties := []interface{}{
+		[]interface{}{"version", map[string]interface{}{}, "text", "4.0"},
+		[]interface{}{"tel", map[string]interface{}{}, "text", "+1 555 555 0100", "+1 555 555 0101"},
+	}
+
+	vcard, err := parseJCard([]interface{}{"vcard", properties})
+	if err != nil {
+		t.Fatalf("Failed to parse jCard: %v", err)
+	}
+
+	// the property is kept in Extra only, and encoded once
+	if len(vcard.Telephones) != 0 || len(vcard.Extra) != 1 {
+		t.Errorf("expected the property in Extra only, got %+v and %+v", vcard.Telephones, vcard.Extra)
+	}
+	if diff := cmp.Diff([]interface{}{"vcard", properties}, encodeJCard(vcard)); diff != "" {
+		t.Errorf("unexpected jCard (-want +got):\n%s", diff)
+	}
+}
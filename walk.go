@@ -0,0 +1,124 @@
+package openrdap
+
+import (
+	"errors"
+	"strings"
+)
+
+// SkipChildren can be returned by an EntityVisitor to skip the entities
+// nested in the visited entity.
+var SkipChildren = errors.New("skip children")
+
+// errStopWalk stops a walk once a result has been found.
+var errStopWalk = errors.New("stop walk")
+
+// EntityPath is the chain of entities from an entity of an object down to a
+// nested entity, e.g. registrar → abuse. The last element is the entity the
+// path leads to. The entities point into the object, not to copies.
+type EntityPath []*Entity
+
+// Entity returns the entity the path leads to.
+func (p EntityPath) Entity() *Entity {
+	if len(p) == 0 {
+		return nil
+	}
+	return p[len(p)-1]
+}
+
+// String returns the path as roles, or handles for entities without roles,
+// e.g. "registrar > abuse".
+func (p EntityPath) String() string {
+	names := make([]string, len(p))
+	for i, e := range p {
+		if len(e.Roles) > 0 {
+			names[i] = strings.Join(e.Roles, ",")
+		} else {
+			names[i] = e.Handle
+		}
+	}
+	return strings.Join(names, " > ")
+}
+
+// An EntityVisitor is called by WalkEntities for every entity. Returning
+// SkipChildren skips the nested entities, any other error stops the walk.
+type EntityVisitor func(path EntityPath) error
+
+// WalkEntities visits the entities of o and all entities nested in them,
+// depth first, in the order of the response. It returns the error returned
+// by visit, if any, other than SkipChildren.
+func WalkEntities(o Object, visit EntityVisitor) error {
+	return walkEntities(o.GetEntities(), nil, visit)
+}
+
+func walkEntities(entities []Entity, parent EntityPath, visit EntityVisitor) error {
+	for i := range entities {
+		// copy the parent path, visitors may keep it
+		path := make(EntityPath, len(parent), len(parent)+1)
+		copy(path, parent)
+		path = append(path, &entities[i])
+
+		err := visit(path)
+		if err == SkipChildren {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := walkEntities(entities[i].Entities, path, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EntityFilter selects entities by role and handle. An entity matches if it
+// has any of Roles and any of Handles; an empty list matches every entity.
+// Roles and handles are compared case-insensitively.
+type EntityFilter struct {
+	Roles   []string
+	Handles []string
+}
+
+// Match reports whether the entity matches the filter.
+func (f EntityFilter) Match(e *Entity) bool {
+	if len(f.Roles) > 0 && !anyRole(e, f.Roles) {
+		return false
+	}
+	if len(f.Handles) > 0 {
+		for _, handle := range f.Handles {
+			if strings.EqualFold(e.Handle, handle) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func anyRole(e *Entity, roles []string) bool {
+	for _, role := range roles {
+		if e.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindEntities returns the paths of all entities of o, at any depth, which
+// match filter.
+func FindEntities(o Object, filter EntityFilter) []EntityPath {
+	var result []EntityPath
+	WalkEntities(o, func(path EntityPath) error {
+		if filter.Match(path.Entity()) {
+			result = append(result, path)
+		}
+		return nil
+	})
+	return result
+}
+
+// FindEntitiesByRole returns the paths of all entities of o, at any depth,
+// with any of roles.
+func FindEntitiesByRole(o Object, roles ...string) []EntityPath {
+	return FindEntities(o, EntityFilter{Roles: roles})
+}
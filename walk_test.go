@@ -0,0 +1,127 @@
+package openrdap
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testEntityTree() *IPNetwork {
+	return &IPNetwork{
+		Entities: []Entity{
+			{
+				Handle: "ORG-1",
+				Roles:  []string{"registrant"},
+				Entities: []Entity{
+					{
+						Handle: "ADMIN-1",
+						Roles:  []string{"administrative"},
+						Entities: []Entity{
+							{Handle: "ABUSE-1", Roles: []string{"Abuse"}},
+						},
+					},
+					{Handle: "TECH-1", Roles: []string{"technical"}},
+				},
+			},
+			{Handle: "ABUSE-2", Roles: []string{"abuse", "technical"}},
+		},
+	}
+}
+
+func handles(paths []EntityPath) []string {
+	var result []string
+	for _, path := range paths {
+		result = append(result, path.Entity().Handle)
+	}
+	return result
+}
+
+func TestFindEntities(t *testing.T) {
+	network := testEntityTree()
+
+	tests := []struct {
+		name     string
+		filter   EntityFilter
+		expected []string
+	}{
+		{name: "All", filter: EntityFilter{}, expected: []string{"ORG-1", "ADMIN-1", "ABUSE-1", "TECH-1", "ABUSE-2"}},
+		{name: "Deep role", filter: EntityFilter{Roles: []string{"abuse"}}, expected: []string{"ABUSE-1", "ABUSE-2"}},
+		{name: "Several roles", filter: EntityFilter{Roles: []string{"administrative", "technical"}}, expected: []string{"ADMIN-1", "TECH-1", "ABUSE-2"}},
+		{name: "Handle", filter: EntityFilter{Handles: []string{"tech-1"}}, expected: []string{"TECH-1"}},
+		{name: "Role and handle", filter: EntityFilter{Roles: []string{"technical"}, Handles: []string{"ABUSE-2", "ADMIN-1"}}, expected: []string{"ABUSE-2"}},
+		{name: "No match", filter: EntityFilter{Roles: []string{"registrar"}}, expected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := handles(FindEntities(network, tt.filter)); !cmp.Equal(result, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, result)
+			}
+		})
+	}
+
+	paths := FindEntitiesByRole(network, "abuse")
+	if paths[0].String() != "registrant > administrative > Abuse" {
+		t.Errorf("unexpected path %s", paths[0])
+	}
+}
+
+func TestWalkEntities(t *testing.T) {
+	network := testEntityTree()
+
+	// skip the contacts of the registrant
+	var visited []string
+	err := WalkEntities(network, func(path EntityPath) error {
+		visited = append(visited, path.Entity().Handle)
+		if path.Entity().HasRole("registrant") {
+			return SkipChildren
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !cmp.Equal(visited, []string{"ORG-1", "ABUSE-2"}) {
+		t.Errorf("unexpected entities visited %v", visited)
+	}
+
+	errStop := errors.New("stop")
+	visited = nil
+	err = WalkEntities(network, func(path EntityPath) error {
+		visited = append(visited, path.Entity().Handle)
+		if len(path) == 3 {
+			return errStop
+		}
+		return nil
+	})
+	if err != errStop || !cmp.Equal(visited, []string{"ORG-1", "ADMIN-1", "ABUSE-1"}) {
+		t.Errorf("unexpected walk %v, %v", err, visited)
+	}
+}
+
+func TestEntityByRoleDepth(t *testing.T) {
+	fileData, err := os.ReadFile("test/example_ip_8888.json")
+	if err != nil {
+		t.Fatalf("failed to read file: %v", err)
+	}
+
+	var network IPNetwork
+	if err := json.Unmarshal(fileData, &network); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+
+	paths := FindEntitiesByRole(&network, "abuse")
+	if len(paths) != 1 || paths[0].Entity().Handle != "ABUSE5250-ARIN" || paths[0][0].Handle != "GOGL" {
+		t.Errorf("unexpected abuse contacts %v", paths)
+	}
+
+	// three levels deep, beyond the reach of the old two level search
+	tree := testEntityTree()
+	tree.Entities = tree.Entities[:1]
+	if abuse := tree.GetEntityFromRole("abuse"); abuse == nil || abuse.Handle != "ABUSE-1" {
+		t.Errorf("Expected ABUSE-1, got %+v", abuse)
+	}
+}